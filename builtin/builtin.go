@@ -24,3 +24,10 @@ func SystemInit(sys types.System) {
 		rand.Seed(sys.RandomSeed)
 	}
 }
+
+// Register expression evaluator for built-in functions.
+//
+// It's called by the operators package on init.
+func RegisterEvaluator(eval func(expr string, localVars map[string]interface{}) (interface{}, error)) {
+	descriptor.Evaluate = eval
+}
@@ -12,6 +12,7 @@ var constants = types.ConstantMap{
 	"nan":     math.NaN(),
 	"pi":      math.Pi,
 	"e":       math.E,
+	"i":       complex(0, 1),
 	"true":    true,
 	"false":   false,
 	"help":    "Type in the expression you want to calc and press Enter to get the result.\n\tTo define a variable type its name and assign the value with '=' operator.\n\tType 'funcs()' to see all available functions.\n\tType 'vars()' to see all available variables.",
@@ -25,7 +26,7 @@ func HasConstant(name string) bool {
 }
 
 // Register a new constant and add it to the builtin constant map.
-func RegisterConstant[T string | bool | uint64 | int64 | float64](name string, value T) {
+func RegisterConstant[T string | bool | uint64 | int64 | float64 | complex128](name string, value T) {
 	constants[name] = value
 }
 
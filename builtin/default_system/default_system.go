@@ -26,6 +26,7 @@ func init() {
 		ListEnvironments: sysListEnv,
 		WriteEnvironment: sysWriteEnv,
 		ReadEnvironment:  sysReadEnv,
+		ReadFile:         sysReadFile,
 		Exit:             sysExit,
 	}
 	builtin.SystemInit(DefaultSystem)
@@ -118,6 +119,15 @@ func sysReadEnv(name string) (io.ReadCloser, error) {
 	return f, nil
 }
 
+func sysReadFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file '%s'", name)
+	}
+
+	return f, nil
+}
+
 func sysExit(errCode int) {
 	terminal.DisableRawMode()
 	os.Exit(errCode)
@@ -0,0 +1,312 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+func widthArg(arg interface{}) (uint8, error) {
+	width := utils.ToNumber[int64](arg)
+	if width < 1 || width > 64 {
+		return 0, fmt.Errorf("the bit width must be in range [1,64]")
+	}
+	return uint8(width), nil
+}
+
+func SignExtend(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	width, err := widthArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return utils.ToFixedInt(args[0], width, true).Int64(), nil
+}
+
+func ZeroExtend(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	width, err := widthArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return utils.ToFixedInt(args[0], width, false).Bits, nil
+}
+
+// Get the value of args[0] as bits and the optional width from args[i].
+//
+// The width defaults to the width of the fixed-width integer or 64.
+func bitsArg(args []interface{}, i int) (uint64, uint8, error) {
+	if len(args) < 1 || args[0] == nil {
+		return 0, 0, fmt.Errorf("not enough arguments")
+	}
+
+	var width uint8 = 64
+	if x, ok := args[0].(utils.FixedInt); ok {
+		width = x.Width
+	}
+	if len(args) > i {
+		var err error
+		width, err = widthArg(args[i])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return utils.ToFixedInt(args[0], width, false).Bits, width, nil
+}
+
+// Return the bits in the type of x: fixed-width integers keep their type, other values become uint64.
+func bitsResult(x interface{}, v uint64, width uint8) interface{} {
+	v &= utils.NewFixedInt(0, width, false).Mask()
+	if x, ok := x.(utils.FixedInt); ok {
+		return utils.NewFixedInt(v, x.Width, x.Signed)
+	}
+	return v
+}
+
+// Make a function that rotates x left by n bits, negative n rotates in the opposite direction.
+func Rotate(left bool) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("not enough arguments")
+		}
+		x, width, err := bitsArg(args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		n := utils.ToNumber[int64](args[1]) % int64(width)
+		if !left {
+			n = -n
+		}
+		if n < 0 {
+			n += int64(width)
+		}
+		if n == 0 {
+			return bitsResult(args[0], x, width), nil
+		}
+
+		return bitsResult(args[0], x<<n|x>>(int64(width)-n), width), nil
+	}
+}
+
+func ByteSwap(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if width%8 != 0 {
+		return nil, fmt.Errorf("the bit width must be a multiple of 8")
+	}
+	return bitsResult(args[0], bits.ReverseBytes64(x)>>(64-width), width), nil
+}
+
+func BitReverse(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return bitsResult(args[0], bits.Reverse64(x)>>(64-width), width), nil
+}
+
+func LeadingZeros(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return uint64(bits.LeadingZeros64(x) - (64 - int(width))), nil
+}
+
+func TrailingZeros(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if x == 0 {
+		return uint64(width), nil
+	}
+	return uint64(bits.TrailingZeros64(x)), nil
+}
+
+func FindFirstSet(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, _, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if x == 0 {
+		return uint64(0), nil
+	}
+	return uint64(bits.TrailingZeros64(x) + 1), nil
+}
+
+func Parity(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, _, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return uint64(bits.OnesCount64(x) & 1), nil
+}
+
+func IsPowerOfTwo(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, _, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return x != 0 && x&(x-1) == 0, nil
+}
+
+func NextPowerOfTwo(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if x <= 1 {
+		return bitsResult(args[0], 1, width), nil
+	}
+
+	shift := bits.Len64(x - 1)
+	if shift >= int(width) {
+		return nil, fmt.Errorf("the next power of two of %d doesn't fit in %d bits", x, width)
+	}
+	return bitsResult(args[0], uint64(1)<<shift, width), nil
+}
+
+// Make a function that aligns x up or down to the power of two.
+func Align(up bool) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("not enough arguments")
+		}
+		x, width, err := bitsArg(args, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		align := utils.ToNumber[uint64](args[1])
+		if align == 0 || align&(align-1) != 0 {
+			return nil, fmt.Errorf("the alignment must be a power of two")
+		}
+
+		if up {
+			aligned := (x + align - 1) &^ (align - 1)
+			if aligned < x || aligned&^utils.NewFixedInt(0, width, false).Mask() != 0 {
+				return nil, fmt.Errorf("the aligned value doesn't fit in %d bits", width)
+			}
+			x = aligned
+		} else {
+			x &^= align - 1
+		}
+
+		return bitsResult(args[0], x, width), nil
+	}
+}
+
+// Deposit the low bits of x to the one bit positions of mask.
+func deposit(x, mask uint64) uint64 {
+	var r uint64
+	for bit := uint64(1); mask != 0; bit <<= 1 {
+		lowest := mask & -mask
+		if x&bit != 0 {
+			r |= lowest
+		}
+		mask &^= lowest
+	}
+	return r
+}
+
+// Extract the bits of x at the one bit positions of mask to the low bits.
+func extract(x, mask uint64) uint64 {
+	var r uint64
+	for bit := uint64(1); mask != 0; bit <<= 1 {
+		lowest := mask & -mask
+		if x&lowest != 0 {
+			r |= bit
+		}
+		mask &^= lowest
+	}
+	return r
+}
+
+func Deposit(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	x, width, err := bitsArg(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	return bitsResult(args[0], deposit(x, utils.ToNumber[uint64](args[1])), width), nil
+}
+
+func Extract(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	x, width, err := bitsArg(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	return bitsResult(args[0], extract(x, utils.ToNumber[uint64](args[1])), width), nil
+}
+
+func GrayEncode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return bitsResult(args[0], x^(x>>1), width), nil
+}
+
+func GrayDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	for shift := 1; shift < 64; shift <<= 1 {
+		x ^= x >> shift
+	}
+	return bitsResult(args[0], x, width), nil
+}
+
+func BCDEncode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var r uint64
+	for shift := 0; x != 0; shift += 4 {
+		if shift+4 > int(width) {
+			return nil, fmt.Errorf("the decimal digits don't fit in %d bits", width)
+		}
+		r |= (x % 10) << shift
+		x /= 10
+	}
+
+	return bitsResult(args[0], r, width), nil
+}
+
+func BCDDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, width, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var r, mul uint64 = 0, 1
+	for shift := 0; shift < int(width); shift += 4 {
+		digit := (x >> shift) & 0xF
+		if digit > 9 {
+			return nil, fmt.Errorf("wrong BCD digit 0x%X at bits [%d:%d]", digit, shift+3, shift)
+		}
+		r += digit * mul
+		mul *= 10
+	}
+
+	return r, nil
+}
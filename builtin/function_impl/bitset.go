@@ -0,0 +1,122 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Max bit index accepted by the bit set functions.
+const maxBitIndex = 4095
+
+// Get the bits of x as non-negative *big.Int, negative values use 64-bit two's complement.
+func bitSet(x interface{}) *big.Int {
+	switch v := x.(type) {
+	case *big.Int:
+		if v.Sign() >= 0 {
+			return new(big.Int).Set(v)
+		}
+	case utils.FixedInt:
+		return new(big.Int).SetUint64(v.Bits)
+	}
+	return new(big.Int).SetUint64(utils.ToFixedInt(x, 64, false).Bits)
+}
+
+func bitIndex(x interface{}) (int, error) {
+	i := utils.ToNumber[int64](x)
+	if i < 0 || i > maxBitIndex {
+		return 0, fmt.Errorf("the bit index %d is out of range [0,%d]", i, maxBitIndex)
+	}
+	return int(i), nil
+}
+
+// Build the bit set from the list of indices.
+func bitSetFromIndices(indices []interface{}) (*big.Int, error) {
+	r := new(big.Int)
+	for _, arg := range indices {
+		i, err := bitIndex(arg)
+		if err != nil {
+			return nil, err
+		}
+		r.SetBit(r, i, 1)
+	}
+	return r, nil
+}
+
+// Return the ascending list of set bit indices of x.
+func bitIndices(x *big.Int) []interface{} {
+	r := make([]interface{}, 0)
+	for i := 0; i < x.BitLen(); i++ {
+		if x.Bit(i) != 0 {
+			r = append(r, uint64(i))
+		}
+	}
+	return r
+}
+
+func Mask(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	hi, err := bitIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	lo := 0
+	if len(args) > 1 {
+		lo, err = bitIndex(args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hi < lo {
+		return nil, fmt.Errorf("the high bit %d of mask is less than the low bit %d", hi, lo)
+	}
+
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(hi-lo+1))
+	mask.Sub(mask, big.NewInt(1))
+	return utils.NormalizeBigInt(mask.Lsh(mask, uint(lo)), true), nil
+}
+
+func BitsOf(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	return bitIndices(bitSet(args[0])), nil
+}
+
+func FromBits(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return uint64(0), nil
+	}
+	r, err := bitSetFromIndices(args)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeBigInt(r, true), nil
+}
+
+// Combine the bit sets of two masks and return the list of set bit indices.
+//
+// Index lists can't be taken here, because arrays in the arguments are merged into one list.
+func bitsOp(args []interface{}, op func(z, x, y *big.Int) *big.Int) (interface{}, error) {
+	if len(args) != 2 || args[0] == nil {
+		return nil, fmt.Errorf("expected two masks, use frombits to convert index lists to masks")
+	}
+	x := bitSet(args[0])
+	return bitIndices(op(x, x, bitSet(args[1]))), nil
+}
+
+func BitsUnion(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return bitsOp(args, (*big.Int).Or)
+}
+
+func BitsIntersection(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return bitsOp(args, (*big.Int).And)
+}
+
+func BitsDifference(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return bitsOp(args, (*big.Int).AndNot)
+}
@@ -0,0 +1,125 @@
+package functionimpl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Max length of the byte array converted from an integer.
+const maxBytesLength = 1024
+
+// Get the byte order from args[i], "be" is the default.
+func byteOrderArg(args []interface{}, i int) (littleEndian bool, err error) {
+	if len(args) <= i {
+		return false, nil
+	}
+	switch args[i] {
+	case "le":
+		return true, nil
+	case "be":
+		return false, nil
+	case "host":
+		return utils.HostByteOrder == binary.LittleEndian, nil
+	}
+	return false, fmt.Errorf("the byte order must be \"le\", \"be\" or \"host\"")
+}
+
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+func Len(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case nil:
+			return uint64(0), nil
+		case []byte:
+			return uint64(len(v)), nil
+		case string:
+			return uint64(len(v)), nil
+		}
+	}
+	return uint64(len(args)), nil
+}
+
+func ToBytes(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	if s, ok := args[0].(string); ok {
+		return []byte(s), nil
+	}
+
+	x := utils.ToBigInt(args[0])
+	var n int64
+	if len(args) > 1 {
+		n = utils.ToNumber[int64](args[1])
+		if n < 1 || n > maxBytesLength {
+			return nil, fmt.Errorf("the number of bytes must be in range [1,%d]", maxBytesLength)
+		}
+	} else if f, ok := args[0].(utils.FixedInt); ok {
+		n = int64(f.Width+7) / 8
+	} else {
+		n = int64(x.BitLen()+7) / 8
+		if x.Sign() < 0 {
+			n = 8
+		}
+		if n == 0 {
+			n = 1
+		}
+	}
+	littleEndian, err := byteOrderArg(args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.Sign() < 0 {
+		x.Add(x, new(big.Int).Lsh(bigOne, uint(n*8)))
+	}
+	if x.Sign() < 0 || int64(x.BitLen()) > n*8 {
+		return nil, fmt.Errorf("the value %v doesn't fit in the byte array of length %d", args[0], n)
+	}
+
+	b := x.FillBytes(make([]byte, n))
+	if littleEndian {
+		reverseBytes(b)
+	}
+	return b, nil
+}
+
+func FromBytes(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	data, err := bytesArg(args[:1])
+	if err != nil {
+		return nil, err
+	}
+	littleEndian, err := byteOrderArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	signed := len(args) > 2 && utils.ToBool(args[2])
+
+	b := append([]byte{}, data...)
+	if littleEndian {
+		reverseBytes(b)
+	}
+	x := new(big.Int).SetBytes(b)
+
+	switch {
+	case len(b) == 0:
+		return uint64(0), nil
+	case len(b) <= 8:
+		return utils.NewFixedInt(x.Uint64(), uint8(len(b)*8), signed), nil
+	case signed && x.Bit(len(b)*8-1) != 0:
+		x.Sub(x, new(big.Int).Lsh(bigOne, uint(len(b)*8)))
+	}
+	return utils.NormalizeBigInt(x, !signed), nil
+}
@@ -0,0 +1,256 @@
+package functionimpl
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/user"
+	"github.com/dece2183/hexowl/utils"
+)
+
+var (
+	cDefine      = regexp.MustCompile(`^\s*#\s*define\s+([A-Za-z_]\w*)(\(?)\s*(.*)$`)
+	cEnumKeyword = regexp.MustCompile(`\benum\b`)
+	cEnum        = regexp.MustCompile(`(?s)\benum\b[^{;]*\{(.*?)\}`)
+	cEnumEntry   = regexp.MustCompile(`(?s)^\s*([A-Za-z_]\w*)\s*(?:=\s*(.+?))?\s*$`)
+	cHexSuffix   = regexp.MustCompile(`\b(0[xX][0-9A-Fa-f]+)[uUlL]+\b`)
+	cUnsigned    = regexp.MustCompile(`\b(\d+)[lL]*[uU][lL]*\b`)
+	cDecSuffix   = regexp.MustCompile(`\b(\d+(?:\.\d*)?(?:[eE][-+]?\d+)?)[uUlLfF]+\b`)
+	cCast        = regexp.MustCompile(`\(\s*(?:(?:const|volatile|unsigned|signed|long|short|int|char|size_t|u?int(?:8|16|32|64)_t)\s*)+\)`)
+	cFloat       = regexp.MustCompile(`\d\.|\.\d|\b\d+[eE]`)
+	cDivision    = regexp.MustCompile(`'(?:\\.|[^'\\])+'|"(?:\\.|[^"\\])*"|/`)
+	cToken       = regexp.MustCompile(`^(?:\s+|0[xX][0-9A-Fa-f]+|\d+(?:\.\d*)?(?:[eE][-+]?\d+)?|\.\d+(?:[eE][-+]?\d+)?|([A-Za-z_]\w*)|'(?:\\.|[^'\\])+'|("(?:\\.|[^"\\])*")|//|<<|>>|<=|>=|==|!=|&&|\|\||[-+*/%&|^~!<>()])`)
+)
+
+// Header constant that was not imported.
+type skippedConstant struct {
+	name   string
+	reason string
+}
+
+// Remove comments from C source, the comment markers inside string and char literals are kept.
+func stripCComments(text string) string {
+	var s strings.Builder
+	for i := 0; i < len(text); {
+		switch {
+		case text[i] == '"' || text[i] == '\'':
+			j := i + 1
+			for j < len(text) && text[j] != text[i] && text[j] != '\n' {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(text) && text[j] == text[i] {
+				j++
+			}
+			if j > len(text) {
+				j = len(text)
+			}
+			s.WriteString(text[i:j])
+			i = j
+		case strings.HasPrefix(text[i:], "//"):
+			n := strings.IndexByte(text[i:], '\n')
+			if n < 0 {
+				return s.String()
+			}
+			i += n
+		case strings.HasPrefix(text[i:], "/*"):
+			n := strings.Index(text[i+2:], "*/")
+			if n < 0 {
+				return s.String()
+			}
+			s.WriteByte(' ')
+			i += n + 4
+		default:
+			s.WriteByte(text[i])
+			i++
+		}
+	}
+	return s.String()
+}
+
+// Translate C expression to the hexowl one.
+func translateCExpr(expr string) string {
+	expr = cHexSuffix.ReplaceAllString(expr, "$1")
+	// decimal integers are signed in hexowl, so the unsigned ones are written in hex
+	expr = cUnsigned.ReplaceAllStringFunc(expr, func(s string) string {
+		digits := cUnsigned.FindStringSubmatch(s)[1]
+		v, err := strconv.ParseUint(digits, 10, 64)
+		if err != nil {
+			return digits
+		}
+		return fmt.Sprintf("0x%X", v)
+	})
+	expr = cDecSuffix.ReplaceAllString(expr, "$1")
+	expr = cCast.ReplaceAllString(expr, "")
+	if !cFloat.MatchString(expr) {
+		// division of integers truncates in C, the literals are kept as is
+		expr = cDivision.ReplaceAllStringFunc(expr, func(s string) string {
+			if s == "/" {
+				return "//"
+			}
+			return s
+		})
+	}
+	return strings.TrimSpace(expr)
+}
+
+// Check that the translated C expression has only numbers, operators and the already imported
+// constants, so a header can't call functions or change variables.
+func checkCExpr(expr string, consts map[string]interface{}) error {
+	for rest := expr; rest != ""; {
+		m := cToken.FindStringSubmatch(rest)
+		if m == nil {
+			r, _ := utf8.DecodeRuneInString(rest)
+			return fmt.Errorf("unexpected '%c'", r)
+		}
+		rest = rest[len(m[0]):]
+		switch {
+		case m[1] != "":
+			if strings.HasPrefix(strings.TrimSpace(rest), "(") {
+				return fmt.Errorf("function call '%s'", m[1])
+			}
+			if _, found := consts[m[1]]; !found {
+				return fmt.Errorf("unknown name '%s'", m[1])
+			}
+		case m[2] != "":
+			return fmt.Errorf("not a number")
+		}
+	}
+	return nil
+}
+
+func CImport(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if desc.System.ReadFile == nil {
+		return nil, fmt.Errorf("'cimport' not implemented")
+	}
+	if desc.Evaluate == nil {
+		return nil, fmt.Errorf("expression evaluator is not registered")
+	}
+
+	path, isString := args[0].(string)
+	if !isString {
+		return nil, fmt.Errorf("the header path must be a string")
+	}
+	var prefix string
+	if len(args) > 1 {
+		prefix, isString = args[1].(string)
+		if !isString {
+			return nil, fmt.Errorf("the name prefix must be a string")
+		}
+	}
+
+	f, err := desc.System.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file '%s'", path)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\\\n", " ")
+	text = stripCComments(text)
+
+	// already imported constants without prefix
+	consts := make(map[string]interface{})
+	skipped := make([]skippedConstant, 0)
+
+	define := func(name, expr string) {
+		expr = translateCExpr(expr)
+		if err := checkCExpr(expr, consts); err != nil {
+			skipped = append(skipped, skippedConstant{name, err.Error()})
+			return
+		}
+		val, err := desc.Evaluate(expr, consts)
+		if err != nil {
+			skipped = append(skipped, skippedConstant{name, err.Error()})
+			return
+		}
+		switch val.(type) {
+		case string, bool, nil, []interface{}:
+			skipped = append(skipped, skippedConstant{name, "not a number"})
+			return
+		}
+		consts[name] = val
+		user.SetVariable(prefix+name, val)
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		if m := cDefine.FindStringSubmatch(lines[i]); m != nil {
+			switch {
+			case m[2] == "(":
+				skipped = append(skipped, skippedConstant{m[1], "function-like macro"})
+			case strings.TrimSpace(m[3]) == "":
+				skipped = append(skipped, skippedConstant{m[1], "no value"})
+			default:
+				define(m[1], m[3])
+			}
+			continue
+		}
+
+		if !cEnumKeyword.MatchString(lines[i]) {
+			continue
+		}
+
+		// collect the whole enum block, the declarations without the body end with ';'
+		start := i
+		block := lines[i]
+		for !strings.ContainsAny(block, "};") && i+1 < len(lines) {
+			i++
+			block += "\n" + lines[i]
+		}
+		m := cEnum.FindStringSubmatch(block)
+		if m == nil {
+			// not an enum definition, the collected lines are processed as usual
+			i = start
+			continue
+		}
+
+		var next interface{} = int64(0)
+		for _, entry := range strings.Split(m[1], ",") {
+			e := cEnumEntry.FindStringSubmatch(entry)
+			if e == nil {
+				if strings.TrimSpace(entry) != "" {
+					skipped = append(skipped, skippedConstant{strings.TrimSpace(entry), "wrong enum entry"})
+				}
+				continue
+			}
+			name := e[1]
+			if e[2] != "" {
+				define(name, e[2])
+			} else if next != nil {
+				consts[name] = next
+				user.SetVariable(prefix+name, next)
+			} else {
+				skipped = append(skipped, skippedConstant{name, "previous enum value is unknown"})
+			}
+
+			if val, ok := consts[name]; ok {
+				next = utils.ToNumber[int64](val) + 1
+			} else {
+				next = nil
+			}
+		}
+	}
+
+	fmt.Fprintf(desc.System.Stdout, "\n\tImported %d constants from '%s'\n", len(consts), path)
+	if len(skipped) > 0 {
+		fmt.Fprintf(desc.System.Stdout, "\tSkipped %d:\n", len(skipped))
+		for _, s := range skipped {
+			fmt.Fprintf(desc.System.Stdout, "\t\t%-24s%s\n", s.name, s.reason)
+		}
+	}
+
+	return uint64(len(consts)), nil
+}
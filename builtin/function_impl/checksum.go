@@ -0,0 +1,244 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// CRC parameters in the Rocksoft model.
+type crcModel struct {
+	width  uint8
+	poly   uint64
+	init   uint64
+	refin  bool
+	refout bool
+	xorout uint64
+	// CRC of "123456789"
+	check uint64
+}
+
+var crcPresets = map[string]crcModel{
+	"CRC-8":              {8, 0x07, 0x00, false, false, 0x00, 0xF4},
+	"CRC-8/MAXIM":        {8, 0x31, 0x00, true, true, 0x00, 0xA1},
+	"CRC-8/SAE-J1850":    {8, 0x1D, 0xFF, false, false, 0xFF, 0x4B},
+	"CRC-16/ARC":         {16, 0x8005, 0x0000, true, true, 0x0000, 0xBB3D},
+	"CRC-16/CCITT-FALSE": {16, 0x1021, 0xFFFF, false, false, 0x0000, 0x29B1},
+	"CRC-16/KERMIT":      {16, 0x1021, 0x0000, true, true, 0x0000, 0x2189},
+	"CRC-16/MODBUS":      {16, 0x8005, 0xFFFF, true, true, 0x0000, 0x4B37},
+	"CRC-16/USB":         {16, 0x8005, 0xFFFF, true, true, 0xFFFF, 0xB4C8},
+	"CRC-16/X-25":        {16, 0x1021, 0xFFFF, true, true, 0xFFFF, 0x906E},
+	"CRC-16/XMODEM":      {16, 0x1021, 0x0000, false, false, 0x0000, 0x31C3},
+	"CRC-32":             {32, 0x04C11DB7, 0xFFFFFFFF, true, true, 0xFFFFFFFF, 0xCBF43926},
+	"CRC-32/BZIP2":       {32, 0x04C11DB7, 0xFFFFFFFF, false, false, 0xFFFFFFFF, 0xFC891918},
+	"CRC-32/MPEG-2":      {32, 0x04C11DB7, 0xFFFFFFFF, false, false, 0x00000000, 0x0376E6E7},
+	"CRC-32C":            {32, 0x1EDC6F41, 0xFFFFFFFF, true, true, 0xFFFFFFFF, 0xE3069283},
+	"CRC-64/ECMA-182":    {64, 0x42F0E1EBA9EA3693, 0, false, false, 0, 0x6C40DF5F0B497347},
+	"CRC-64/GO-ISO":      {64, 0x1B, 0xFFFFFFFFFFFFFFFF, true, true, 0xFFFFFFFFFFFFFFFF, 0xB90956C775A41001},
+	"CRC-64/XZ":          {64, 0x42F0E1EBA9EA3693, 0xFFFFFFFFFFFFFFFF, true, true, 0xFFFFFFFFFFFFFFFF, 0x995DC9BBDF1939FA},
+}
+
+// Get the data bytes from the byte arrays, strings and byte values of args.
+func bytesArg(args []interface{}) ([]byte, error) {
+	data := make([]byte, 0, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			continue
+		case string:
+			data = append(data, v...)
+		case []byte:
+			data = append(data, v...)
+		default:
+			b := utils.ToNumber[int64](v)
+			if b < -128 || b > 255 {
+				return nil, fmt.Errorf("the value %v at position %d doesn't fit in a byte", v, i)
+			}
+			data = append(data, byte(b))
+		}
+	}
+	return data, nil
+}
+
+func (m crcModel) mask() uint64 {
+	return utils.NewFixedInt(0, m.width, false).Mask()
+}
+
+func (m crcModel) calc(data []byte) uint64 {
+	mask := m.mask()
+	crc := m.init & mask
+	for _, b := range data {
+		if m.refin {
+			b = bits.Reverse8(b)
+		}
+		for i := 7; i >= 0; i-- {
+			top := crc>>(m.width-1)&1 ^ uint64(b>>i)&1
+			crc = crc << 1 & mask
+			if top != 0 {
+				crc ^= m.poly & mask
+			}
+		}
+	}
+	if m.refout {
+		crc = bits.Reverse64(crc) >> (64 - m.width)
+	}
+	return (crc ^ m.xorout) & mask
+}
+
+func (m crcModel) String() string {
+	digits := int(m.width+3) / 4
+	return fmt.Sprintf("width=%-2d poly=0x%0*X init=0x%0*X refin=%-5t refout=%-5t xorout=0x%0*X check=0x%0*X",
+		m.width, digits, m.poly, digits, m.init, m.refin, m.refout, digits, m.xorout, digits, m.check)
+}
+
+func findCrcPreset(name string) (crcModel, bool) {
+	m, ok := crcPresets[strings.ToUpper(name)]
+	return m, ok
+}
+
+func Crc(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+
+	var model crcModel
+	var data []interface{}
+	if name, ok := args[len(args)-1].(string); ok {
+		model, ok = findCrcPreset(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown CRC preset '%s'", name)
+		}
+		data = args[:len(args)-1]
+	} else {
+		if len(args) < 7 {
+			return nil, fmt.Errorf("not enough arguments")
+		}
+		params := args[len(args)-6:]
+		width, err := widthArg(params[0])
+		if err != nil {
+			return nil, err
+		}
+		model = crcModel{
+			width:  width,
+			poly:   utils.ToNumber[uint64](params[1]),
+			init:   utils.ToNumber[uint64](params[2]),
+			refin:  utils.ToBool(params[3]),
+			refout: utils.ToBool(params[4]),
+			xorout: utils.ToNumber[uint64](params[5]),
+		}
+		data = args[:len(args)-6]
+	}
+
+	bytes, err := bytesArg(data)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewFixedInt(model.calc(bytes), model.width, false), nil
+}
+
+func CrcPresets(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	names := make([]string, 0, len(crcPresets))
+	for name := range crcPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(desc.System.Stdout, "\n\tCRC presets:\n")
+	for _, name := range names {
+		fmt.Fprintf(desc.System.Stdout, "\t\t%-20s %s\n", name, crcPresets[name])
+	}
+
+	return uint64(len(names)), nil
+}
+
+func Adler32(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var a, b uint64 = 1, 0
+	for _, d := range data {
+		a = (a + uint64(d)) % 65521
+		b = (b + a) % 65521
+	}
+	return utils.NewFixedInt(b<<16|a, 32, false), nil
+}
+
+func Fletcher16(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var sum1, sum2 uint64
+	for _, d := range data {
+		sum1 = (sum1 + uint64(d)) % 255
+		sum2 = (sum2 + sum1) % 255
+	}
+	return utils.NewFixedInt(sum2<<8|sum1, 16, false), nil
+}
+
+// Fletcher-32 over little-endian 16-bit words, the odd byte is padded with zero.
+func Fletcher32(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var sum1, sum2 uint64
+	for i := 0; i < len(data); i += 2 {
+		word := uint64(data[i])
+		if i+1 < len(data) {
+			word |= uint64(data[i+1]) << 8
+		}
+		sum1 = (sum1 + word) % 65535
+		sum2 = (sum2 + sum1) % 65535
+	}
+	return utils.NewFixedInt(sum2<<16|sum1, 32, false), nil
+}
+
+// Internet checksum (RFC 1071) over big-endian 16-bit words.
+func InternetChecksum(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var sum uint64
+	for i := 0; i < len(data); i += 2 {
+		word := uint64(data[i]) << 8
+		if i+1 < len(data) {
+			word |= uint64(data[i+1])
+		}
+		sum += word
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xFFFF + sum>>16
+	}
+	return utils.NewFixedInt(^sum, 16, false), nil
+}
+
+func Xor8(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var r byte
+	for _, d := range data {
+		r ^= d
+	}
+	return utils.NewFixedInt(uint64(r), 8, false), nil
+}
+
+func Sum8(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	var r byte
+	for _, d := range data {
+		r += d
+	}
+	return utils.NewFixedInt(uint64(r), 8, false), nil
+}
@@ -0,0 +1,58 @@
+package functionimpl
+
+import (
+	"math"
+	"math/big"
+	"math/cmplx"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+func Abs(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	switch x := args[0].(type) {
+	case complex128:
+		return cmplx.Abs(x), nil
+	case float64:
+		return math.Abs(x), nil
+	case int64:
+		if x < 0 {
+			return -x, nil
+		}
+		return x, nil
+	case *big.Int:
+		return utils.NormalizeBigInt(new(big.Int).Abs(x), false), nil
+	case *big.Rat:
+		return new(big.Rat).Abs(x), nil
+	case utils.FixedInt:
+		if x.IsNegative() {
+			return utils.NewFixedInt(uint64(-x.Int64()), x.Width, x.Signed), nil
+		}
+		return x, nil
+	case nil:
+		return uint64(0), nil
+	}
+	return args[0], nil
+}
+
+func Arg(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return cmplx.Phase(utils.ToComplex(args[0])), nil
+}
+
+func Conj(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if x, ok := args[0].(complex128); ok {
+		return cmplx.Conj(x), nil
+	}
+	return args[0], nil
+}
+
+func Real(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if x, ok := args[0].(complex128); ok {
+		return real(x), nil
+	}
+	return args[0], nil
+}
+
+func Imag(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return imag(utils.ToComplex(args[0])), nil
+}
@@ -0,0 +1,176 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Hamming code layout for the data word of the given width.
+//
+// Check bit k is at codeword position 2^k, data bits fill the rest of positions in order,
+// positions start from 1.
+type hammingCode struct {
+	checkBits int
+	positions []int
+}
+
+func newHammingCode(dataBits int) hammingCode {
+	h := hammingCode{positions: make([]int, 0, dataBits)}
+	for (1 << h.checkBits) < dataBits+h.checkBits+1 {
+		h.checkBits++
+	}
+	for pos := 1; len(h.positions) < dataBits; pos++ {
+		if pos&(pos-1) != 0 {
+			h.positions = append(h.positions, pos)
+		}
+	}
+	return h
+}
+
+// Check bits of the data, bit k is the parity of data bits at positions with bit k set.
+func (h hammingCode) check(data uint64) uint64 {
+	var check uint64
+	for i, pos := range h.positions {
+		if data>>i&1 != 0 {
+			check ^= uint64(pos)
+		}
+	}
+	return check
+}
+
+// The length of the codeword without the overall parity bit.
+func (h hammingCode) length() int {
+	return len(h.positions) + h.checkBits
+}
+
+// Describe the single-bit error at the codeword position and correct the data.
+func (h hammingCode) correct(data uint64, pos int) (uint64, string) {
+	if pos&(pos-1) == 0 {
+		return data, fmt.Sprintf("Corrected single-bit error in check bit %d (position %d)", bits.TrailingZeros(uint(pos)), pos)
+	}
+	for i, p := range h.positions {
+		if p == pos {
+			return data ^ (1 << i), fmt.Sprintf("Corrected single-bit error in data bit %d (position %d)", i, pos)
+		}
+	}
+	return data, fmt.Sprintf("Detected uncorrectable error, the syndrome points to position %d outside the codeword", pos)
+}
+
+var hamming74 = newHammingCode(4)
+
+// Get the SECDED data width from args[i], it defaults to the width of the fixed-width integer.
+func secdedArgs(args []interface{}, i int) (uint64, uint8, error) {
+	data, width, err := bitsArg(args, i)
+	if err != nil {
+		return 0, 0, err
+	}
+	switch width {
+	case 8, 16, 32, 64:
+		return data, width, nil
+	}
+	return 0, 0, fmt.Errorf("the data width must be 8, 16, 32 or 64 bits")
+}
+
+func OddParity(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, _, err := bitsArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return uint64(^bits.OnesCount64(x) & 1), nil
+}
+
+func Hamming74(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	data := utils.ToNumber[uint64](args[0])
+	if data > 0xF {
+		return nil, fmt.Errorf("the data must fit in 4 bits")
+	}
+
+	check := hamming74.check(data)
+	var codeword uint64
+	for i, pos := range hamming74.positions {
+		codeword |= (data >> i & 1) << (pos - 1)
+	}
+	for k := 0; k < hamming74.checkBits; k++ {
+		codeword |= (check >> k & 1) << ((1 << k) - 1)
+	}
+
+	return codeword, nil
+}
+
+func Hamming74Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	codeword := utils.ToNumber[uint64](args[0])
+	if codeword > 0x7F {
+		return nil, fmt.Errorf("the codeword must fit in 7 bits")
+	}
+
+	// the syndrome is the XOR of positions of all one bits
+	var syndrome, data uint64
+	for pos := 1; pos <= hamming74.length(); pos++ {
+		if codeword>>(pos-1)&1 != 0 {
+			syndrome ^= uint64(pos)
+		}
+	}
+	for i, pos := range hamming74.positions {
+		data |= (codeword >> (pos - 1) & 1) << i
+	}
+
+	status := "No error"
+	if syndrome != 0 {
+		data, status = hamming74.correct(data, int(syndrome))
+	}
+	fmt.Fprintf(desc.System.Stdout, "\n\t%s\n", status)
+
+	return []interface{}{data, syndrome}, nil
+}
+
+func Secded(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, width, err := secdedArgs(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	h := newHammingCode(int(width))
+	check := h.check(data)
+	overall := uint64(bits.OnesCount64(data)+bits.OnesCount64(check)) & 1
+	return check | overall<<h.checkBits, nil
+}
+
+func SecdedDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	data, width, err := secdedArgs(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	h := newHammingCode(int(width))
+	received := utils.ToNumber[uint64](args[1]) & (1<<(h.checkBits+1) - 1)
+
+	syndrome := h.check(data) ^ received&(1<<h.checkBits-1)
+	parityErr := uint64(bits.OnesCount64(data)+bits.OnesCount64(received)) & 1
+
+	var status string
+	switch {
+	case syndrome == 0 && parityErr == 0:
+		status = "No error"
+	case syndrome == 0:
+		status = "Corrected single-bit error in the overall parity bit"
+	case parityErr == 0:
+		status = "Detected double-bit error"
+	case int(syndrome) > h.length():
+		status = fmt.Sprintf("Detected uncorrectable error, the syndrome points to position %d outside the codeword", syndrome)
+	default:
+		data, status = h.correct(data, int(syndrome))
+	}
+	fmt.Fprintf(desc.System.Stdout, "\n\t%s\n", status)
+
+	return []interface{}{bitsResult(args[0], data, width), syndrome | parityErr<<h.checkBits}, nil
+}
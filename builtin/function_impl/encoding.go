@@ -0,0 +1,348 @@
+package functionimpl
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime/quotedprintable"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+)
+
+var base64Encodings = map[string]*base64.Encoding{
+	"std":    base64.StdEncoding,
+	"url":    base64.URLEncoding,
+	"rawstd": base64.RawStdEncoding,
+	"rawurl": base64.RawURLEncoding,
+}
+
+var base32Encodings = map[string]*base32.Encoding{
+	"std":    base32.StdEncoding,
+	"hex":    base32.HexEncoding,
+	"rawstd": base32.StdEncoding.WithPadding(base32.NoPadding),
+	"rawhex": base32.HexEncoding.WithPadding(base32.NoPadding),
+}
+
+const hexDigits = "0123456789ABCDEF"
+
+// Get the encoded text from args[i].
+func textArg(args []interface{}, i int) (string, error) {
+	if len(args) <= i || args[i] == nil {
+		return "", fmt.Errorf("not enough arguments")
+	}
+	switch v := args[i].(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", fmt.Errorf("the encoded text must be a string")
+}
+
+// Split args into the data and the option like the separator or the variant name.
+//
+// The option is taken only if exactly two arguments are passed and the second one is a string,
+// otherwise all the arguments are the data.
+func optionArg(args []interface{}) (data []interface{}, option string, ok bool) {
+	if len(args) == 2 {
+		if s, isString := args[1].(string); isString {
+			return args[:1], s, true
+		}
+	}
+	return args, "", false
+}
+
+// Get the encoding variant name from the option of args.
+func variantArg[T any](args []interface{}, encoding string, variants map[string]T) (name string, data []interface{}, err error) {
+	data, name, ok := optionArg(args)
+	if ok {
+		if _, found := variants[name]; !found {
+			return "", nil, fmt.Errorf("unknown %s variant '%s'", encoding, name)
+		}
+	}
+	return name, data, nil
+}
+
+// Guess the variant of base64 or base32 text without the name.
+//
+// The text is unpadded if it has no padding and its length is not a multiple of the block size.
+func guessVariant(text, alphabet string, block int) string {
+	variant := "std"
+	if strings.ContainsAny(text, "-_") {
+		variant = alphabet
+	}
+	if !strings.Contains(text, "=") && len(strings.TrimSpace(text))%block != 0 {
+		variant = "raw" + variant
+	}
+	return variant
+}
+
+func HexEncode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	args, sep, _ := optionArg(args)
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var s strings.Builder
+	for i, b := range data {
+		if i > 0 {
+			s.WriteString(sep)
+		}
+		s.WriteByte(hexDigits[b>>4])
+		s.WriteByte(hexDigits[b&0xF])
+	}
+	return s.String(), nil
+}
+
+func HexDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, len(text)/2)
+	var digit byte
+	digits := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		case strings.IndexByte(" \t\r\n:-_", c) >= 0 && digits%2 == 0:
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' at position %d of hex string", c, i)
+		}
+		if digits%2 == 0 {
+			digit = v << 4
+		} else {
+			data = append(data, digit|v)
+		}
+		digits++
+	}
+	if digits%2 != 0 {
+		return nil, fmt.Errorf("the hex string must have an even number of digits")
+	}
+
+	return data, nil
+}
+
+func Base64Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	variant, args, err := variantArg(args, "base64", base64Encodings)
+	if err != nil {
+		return nil, err
+	}
+	if variant == "" {
+		variant = "std"
+	}
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return base64Encodings[variant].EncodeToString(data), nil
+}
+
+func Base64Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	variant, args, err := variantArg(args, "base64", base64Encodings)
+	if err != nil {
+		return nil, err
+	}
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if variant == "" {
+		variant = guessVariant(text, "url", 4)
+	}
+
+	data, err := base64Encodings[variant].DecodeString(text)
+	var corrupt base64.CorruptInputError
+	if errors.As(err, &corrupt) {
+		return nil, fmt.Errorf("unexpected data at position %d of %s base64 text", corrupt, variant)
+	}
+	return data, err
+}
+
+func Base32Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	variant, args, err := variantArg(args, "base32", base32Encodings)
+	if err != nil {
+		return nil, err
+	}
+	if variant == "" {
+		variant = "std"
+	}
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return base32Encodings[variant].EncodeToString(data), nil
+}
+
+func Base32Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	variant, args, err := variantArg(args, "base32", base32Encodings)
+	if err != nil {
+		return nil, err
+	}
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if variant == "" {
+		variant = guessVariant(text, "std", 8)
+	}
+
+	data, err := base32Encodings[variant].DecodeString(text)
+	var corrupt base32.CorruptInputError
+	if errors.As(err, &corrupt) {
+		return nil, fmt.Errorf("unexpected data at position %d of %s base32 text", corrupt, variant)
+	}
+	return data, err
+}
+
+func Ascii85Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	return string(dst[:ascii85.Encode(dst, data)]), nil
+}
+
+func Ascii85Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adobe delimiters
+	offset := 0
+	if strings.HasPrefix(text, "<~") {
+		text = text[2:]
+		offset = 2
+	}
+	text = strings.TrimSuffix(text, "~>")
+
+	dst := make([]byte, 4*len(text))
+	n, _, err := ascii85.Decode(dst, []byte(text), true)
+	var corrupt ascii85.CorruptInputError
+	if errors.As(err, &corrupt) {
+		return nil, fmt.Errorf("unexpected data at position %d of ascii85 text", int(corrupt)+offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// Percent-encode all bytes except the unreserved characters of RFC 3986.
+func UrlEncode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var s strings.Builder
+	for _, b := range data {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', strings.IndexByte("-._~", b) >= 0:
+			s.WriteByte(b)
+		default:
+			s.WriteByte('%')
+			s.WriteByte(hexDigits[b>>4])
+			s.WriteByte(hexDigits[b&0xF])
+		}
+	}
+	return s.String(), nil
+}
+
+func UrlDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var s strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' {
+			s.WriteByte(text[i])
+			continue
+		}
+		b, ok := decodeHexByte(text, i+1)
+		if !ok {
+			return nil, fmt.Errorf("wrong percent-encoded byte at position %d", i)
+		}
+		s.WriteByte(b)
+		i += 2
+	}
+	return s.String(), nil
+}
+
+func QuotedPrintableEncode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.String(), nil
+}
+
+func QuotedPrintableDecode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	text, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var s strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '=' {
+			s.WriteByte(text[i])
+			continue
+		}
+		// soft line break
+		if strings.HasPrefix(text[i+1:], "\r\n") {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(text[i+1:], "\n") {
+			i++
+			continue
+		}
+		b, ok := decodeHexByte(text, i+1)
+		if !ok {
+			return nil, fmt.Errorf("wrong quoted-printable byte at position %d", i)
+		}
+		s.WriteByte(b)
+		i += 2
+	}
+	return s.String(), nil
+}
+
+// Decode two hex digits of text at position i.
+func decodeHexByte(text string, i int) (byte, bool) {
+	if i+2 > len(text) {
+		return 0, false
+	}
+	var b byte
+	for _, c := range []byte(strings.ToUpper(text[i : i+2])) {
+		v := strings.IndexByte(hexDigits, c)
+		if v < 0 {
+			return 0, false
+		}
+		b = b<<4 | byte(v)
+	}
+	return b, true
+}
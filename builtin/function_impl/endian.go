@@ -0,0 +1,47 @@
+package functionimpl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Make a function that converts x between the host byte order and the given one.
+//
+// The conversion is symmetric, so the same function converts to and from the host order.
+func HostByteOrder(order binary.ByteOrder) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		if len(args) < 1 || args[0] == nil {
+			return nil, fmt.Errorf("not enough arguments")
+		}
+
+		width := 64
+		if x, ok := args[0].(utils.FixedInt); ok {
+			width = int(x.Width)
+		}
+		if len(args) > 1 {
+			width = int(utils.ToNumber[int64](args[1]))
+		}
+		switch width {
+		case 16, 32, 64, 128:
+		default:
+			return nil, fmt.Errorf("the bit width must be 16, 32, 64 or 128")
+		}
+
+		x := bitSet(args[0])
+		x.And(x, new(big.Int).Sub(new(big.Int).Lsh(bigOne, uint(width)), bigOne))
+		if order != utils.HostByteOrder {
+			b := x.FillBytes(make([]byte, width/8))
+			reverseBytes(b)
+			x.SetBytes(b)
+		}
+
+		if width <= 64 {
+			return bitsResult(args[0], x.Uint64(), uint8(width)), nil
+		}
+		return utils.NormalizeBigInt(x, true), nil
+	}
+}
@@ -11,8 +11,10 @@ import (
 
 type environment struct {
 	Description string
-	UserVars    map[string]interface{}
+	UserVars    map[string]envVar
 	UserFuncs   map[string]user.Func
+	UserLayouts map[string]user.Layout
+	UserFlags   map[string]user.FlagSet
 }
 
 const (
@@ -67,7 +69,7 @@ func resolveDependencies(desc *types.Descriptor, env environment, unit string, f
 			// Search in user variables
 			for key, val := range env.UserVars {
 				if key == word.Literal {
-					user.SetVariable(key, val)
+					user.SetVariable(key, val.Value)
 					loadedUnits++
 					continue nextWord
 				}
@@ -132,9 +134,15 @@ func Save(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
 	}
 
 	// Save environment
+	userVars := make(map[string]envVar)
+	for name, val := range user.ListVariables() {
+		userVars[name] = envVar{val}
+	}
 	saveData := environment{
-		UserVars:    user.ListVariables(),
+		UserVars:    userVars,
 		UserFuncs:   user.ListFunctions(),
+		UserLayouts: user.ListLayouts(),
+		UserFlags:   user.ListFlagSets(),
 		Description: envDescription,
 	}
 
@@ -179,12 +187,20 @@ func Load(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
 	// Apply loaded environment
 	user.DropVariables()
 	for name, val := range loadData.UserVars {
-		user.SetVariable(name, val)
+		user.SetVariable(name, val.Value)
 	}
 	user.DropFunctions()
 	for name, val := range loadData.UserFuncs {
 		user.SetFunction(name, val)
 	}
+	user.DropLayouts()
+	for name, val := range loadData.UserLayouts {
+		user.SetLayout(name, val)
+	}
+	user.DropFlagSets()
+	for name, val := range loadData.UserFlags {
+		user.SetFlagSet(name, val)
+	}
 
 	fmt.Fprintf(desc.System.Stdout, "\n\tEnvironment '%s' loaded\n", envName)
 	return true, nil
@@ -213,13 +229,21 @@ func ImportUnit(desc *types.Descriptor, args ...interface{}) (interface{}, error
 	if len(args) == 1 {
 		// Import all environment
 		for name, val := range loadedEnv.UserVars {
-			user.SetVariable(name, val)
+			user.SetVariable(name, val.Value)
 			loadedUnits++
 		}
 		for name, val := range loadedEnv.UserFuncs {
 			user.SetFunction(name, val)
 			loadedUnits++
 		}
+		for name, val := range loadedEnv.UserLayouts {
+			user.SetLayout(name, val)
+			loadedUnits++
+		}
+		for name, val := range loadedEnv.UserFlags {
+			user.SetFlagSet(name, val)
+			loadedUnits++
+		}
 	} else {
 		// Try to find units
 		for i := 1; i < len(args); i++ {
@@ -233,7 +257,7 @@ func ImportUnit(desc *types.Descriptor, args ...interface{}) (interface{}, error
 
 			userVar, found := loadedEnv.UserVars[name]
 			if found {
-				user.SetVariable(name, userVar)
+				user.SetVariable(name, userVar.Value)
 				loadedUnits++
 			}
 
@@ -243,6 +267,18 @@ func ImportUnit(desc *types.Descriptor, args ...interface{}) (interface{}, error
 				user.SetFunction(name, userFunc)
 				loadedUnits++
 			}
+
+			userLayout, found := loadedEnv.UserLayouts[name]
+			if found {
+				user.SetLayout(name, userLayout)
+				loadedUnits++
+			}
+
+			userFlags, found := loadedEnv.UserFlags[name]
+			if found {
+				user.SetFlagSet(name, userFlags)
+				loadedUnits++
+			}
 		}
 	}
 
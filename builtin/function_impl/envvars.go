@@ -0,0 +1,164 @@
+package functionimpl
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+// User variable value in the environment file.
+//
+// JSON numbers are decoded as float64, so integers and the typed values
+// are saved as objects with the type tag, other values are saved as is.
+type envVar struct {
+	Value interface{}
+}
+
+// Parts of the complex number, saved as strings to keep infinities and NaN.
+type complexVar struct {
+	Re string `json:"re"`
+	Im string `json:"im"`
+}
+
+type taggedVar struct {
+	Type  string          `json:"$type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func newTaggedVar(typ string, value interface{}) (taggedVar, error) {
+	raw, err := json.Marshal(value)
+	return taggedVar{Type: typ, Value: raw}, err
+}
+
+func (v envVar) MarshalJSON() ([]byte, error) {
+	var out interface{} = v.Value
+	var err error
+
+	switch val := v.Value.(type) {
+	case int64:
+		out, err = newTaggedVar("int", val)
+	case uint64:
+		out, err = newTaggedVar("uint", val)
+	case *big.Int:
+		out, err = newTaggedVar("bigint", val.String())
+	case *big.Rat:
+		out, err = newTaggedVar("rat", val.String())
+	case complex128:
+		out, err = newTaggedVar("complex", complexVar{
+			Re: strconv.FormatFloat(real(val), 'g', -1, 64),
+			Im: strconv.FormatFloat(imag(val), 'g', -1, 64),
+		})
+	case utils.FixedInt:
+		out, err = newTaggedVar("fixedint", val)
+	case utils.Flags:
+		out, err = newTaggedVar("flags", val)
+	case utils.Pattern:
+		out, err = newTaggedVar("pattern", val)
+	case []byte:
+		out, err = newTaggedVar("bytes", hex.EncodeToString(val))
+	case []interface{}:
+		items := make([]envVar, len(val))
+		for i, item := range val {
+			items[i] = envVar{item}
+		}
+		out = items
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(out)
+}
+
+func (v *envVar) UnmarshalJSON(data []byte) error {
+	var tagged taggedVar
+	if json.Unmarshal(data, &tagged) == nil && tagged.Type != "" {
+		return v.unmarshalTagged(tagged)
+	}
+
+	var items []envVar
+	if json.Unmarshal(data, &items) == nil {
+		arr := make([]interface{}, len(items))
+		for i, item := range items {
+			arr[i] = item.Value
+		}
+		v.Value = arr
+		return nil
+	}
+
+	return json.Unmarshal(data, &v.Value)
+}
+
+func (v *envVar) unmarshalTagged(tagged taggedVar) error {
+	var err error
+
+	switch tagged.Type {
+	case "int":
+		var i int64
+		err = json.Unmarshal(tagged.Value, &i)
+		v.Value = i
+	case "uint":
+		var u uint64
+		err = json.Unmarshal(tagged.Value, &u)
+		v.Value = u
+	case "bigint":
+		var s string
+		err = json.Unmarshal(tagged.Value, &s)
+		x, ok := new(big.Int).SetString(s, 10)
+		if err == nil && !ok {
+			err = fmt.Errorf("wrong big integer '%s'", s)
+		}
+		v.Value = x
+	case "rat":
+		var s string
+		err = json.Unmarshal(tagged.Value, &s)
+		r, ok := new(big.Rat).SetString(s)
+		if err == nil && !ok {
+			err = fmt.Errorf("wrong rational number '%s'", s)
+		}
+		v.Value = r
+	case "complex":
+		var c complexVar
+		var re, im float64
+		err = json.Unmarshal(tagged.Value, &c)
+		if err == nil {
+			re, err = strconv.ParseFloat(c.Re, 64)
+		}
+		if err == nil {
+			im, err = strconv.ParseFloat(c.Im, 64)
+		}
+		v.Value = complex(re, im)
+	case "fixedint":
+		var i utils.FixedInt
+		err = json.Unmarshal(tagged.Value, &i)
+		if err == nil && (i.Width < 1 || i.Width > 64) {
+			err = fmt.Errorf("wrong integer width %d", i.Width)
+		}
+		v.Value = utils.NewFixedInt(i.Bits, i.Width, i.Signed)
+	case "flags":
+		var f utils.Flags
+		err = json.Unmarshal(tagged.Value, &f)
+		v.Value = f
+	case "pattern":
+		var p utils.Pattern
+		err = json.Unmarshal(tagged.Value, &p)
+		if err == nil && p.Width > 64 {
+			err = fmt.Errorf("wrong pattern width %d", p.Width)
+		}
+		v.Value = p
+	case "bytes":
+		var s string
+		err = json.Unmarshal(tagged.Value, &s)
+		if err == nil {
+			v.Value, err = hex.DecodeString(s)
+		}
+	default:
+		err = fmt.Errorf("unknown variable type '%s'", tagged.Type)
+	}
+
+	return err
+}
@@ -0,0 +1,21 @@
+package functionimpl
+
+import (
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Make a function that converts its arguments to the fixed-width integer type.
+func FixedIntCast(width uint8, signed bool) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		if len(args) == 1 {
+			return utils.ToFixedInt(args[0], width, signed), nil
+		}
+
+		result := make([]interface{}, len(args))
+		for i, arg := range args {
+			result[i] = utils.ToFixedInt(arg, width, signed)
+		}
+		return result, nil
+	}
+}
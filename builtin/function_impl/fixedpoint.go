@@ -0,0 +1,181 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Fixed-point number format Qm.n.
+type qFormat struct {
+	name string
+	// Number of integer bits including the sign bit.
+	intBits uint8
+	// Number of fraction bits.
+	fracBits uint8
+	signed   bool
+}
+
+// Parse Q-format name like Q15, Q8.8 or UQ16.16.
+//
+// The ARM convention is used: m includes the sign bit, Qn is Q1.n and UQn is UQ0.n.
+func parseQFormat(arg interface{}) (qFormat, error) {
+	name, isString := arg.(string)
+	if !isString {
+		return qFormat{}, fmt.Errorf("the Q-format must be a string like \"Q15\" or \"UQ16.16\"")
+	}
+
+	f := qFormat{name: name, signed: true}
+	s := strings.ToUpper(name)
+	if strings.HasPrefix(s, "U") {
+		f.signed = false
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "Q") {
+		return qFormat{}, fmt.Errorf("unable to parse Q-format '%s'", name)
+	}
+	s = s[1:]
+
+	var m, n uint64
+	var err error
+	if pos := strings.IndexByte(s, '.'); pos > -1 {
+		m, err = strconv.ParseUint(s[:pos], 10, 8)
+		if err == nil {
+			n, err = strconv.ParseUint(s[pos+1:], 10, 8)
+		}
+	} else {
+		n, err = strconv.ParseUint(s, 10, 8)
+		if f.signed {
+			m = 1
+		}
+	}
+	if err != nil {
+		return qFormat{}, fmt.Errorf("unable to parse Q-format '%s'", name)
+	}
+
+	if m+n < 1 || m+n > 64 || f.signed && m < 1 {
+		return qFormat{}, fmt.Errorf("the Q-format '%s' must have from 1 to 64 bits including the sign bit", name)
+	}
+
+	f.intBits, f.fracBits = uint8(m), uint8(n)
+	return f, nil
+}
+
+func (f qFormat) width() uint8 {
+	return f.intBits + f.fracBits
+}
+
+// The range of the raw integer values.
+func (f qFormat) limits() (min, max *big.Int) {
+	if f.signed {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(f.width()-1))
+		min = new(big.Int).Neg(max)
+	} else {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(f.width()))
+		min = new(big.Int)
+	}
+	max.Sub(max, big.NewInt(1))
+	return
+}
+
+// Saturate the raw value x to the format range and convert it to the fixed-width integer.
+func (f qFormat) saturate(desc *types.Descriptor, x *big.Int) utils.FixedInt {
+	min, max := f.limits()
+	if x.Cmp(max) > 0 {
+		fmt.Fprintf(desc.System.Stdout, "\n\tSaturated: the value is out of %s range\n", f.name)
+		x = max
+	} else if x.Cmp(min) < 0 {
+		fmt.Fprintf(desc.System.Stdout, "\n\tSaturated: the value is out of %s range\n", f.name)
+		x = min
+	}
+	return utils.NewFixedInt(utils.TruncBigInt(x), f.width(), f.signed)
+}
+
+// The raw integer value of x in the format.
+func (f qFormat) raw(x interface{}) *big.Int {
+	return utils.ToBigInt(utils.ToFixedInt(x, f.width(), f.signed))
+}
+
+// Divide x by y rounding the quotient to nearest, ties away from zero.
+func quoRound(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	r.Abs(r).Lsh(r, 1)
+	if r.CmpAbs(y) >= 0 {
+		if x.Sign() == y.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Apply conversion to every argument except the last one which is the format.
+func qConvert(args []interface{}, conv func(x interface{}, f qFormat) interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	f, err := parseQFormat(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 2 {
+		return conv(args[0], f), nil
+	}
+
+	result := make([]interface{}, len(args)-1)
+	for i, arg := range args[:len(args)-1] {
+		result[i] = conv(arg, f)
+	}
+	return result, nil
+}
+
+func ToQ(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return qConvert(args, func(x interface{}, f qFormat) interface{} {
+		v := new(big.Rat).Mul(utils.ToBigRat(x), new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(f.fracBits))))
+		return f.saturate(desc, quoRound(v.Num(), v.Denom()))
+	})
+}
+
+func FromQ(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return qConvert(args, func(x interface{}, f qFormat) interface{} {
+		v, _ := new(big.Float).SetInt(f.raw(x)).Float64()
+		return math.Ldexp(v, -int(f.fracBits))
+	})
+}
+
+func QMul(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	f, err := parseQFormat(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(big.Int).Mul(f.raw(args[0]), f.raw(args[1]))
+	return f.saturate(desc, quoRound(p, new(big.Int).Lsh(big.NewInt(1), uint(f.fracBits)))), nil
+}
+
+func QDiv(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	f, err := parseQFormat(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	y := f.raw(args[1])
+	if y.Sign() == 0 {
+		return nil, fmt.Errorf("fixed-point division by zero")
+	}
+	x := new(big.Int).Lsh(f.raw(args[0]), uint(f.fracBits))
+	return f.saturate(desc, quoRound(x, y)), nil
+}
@@ -0,0 +1,91 @@
+package functionimpl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/user"
+	"github.com/dece2183/hexowl/utils"
+)
+
+func FlagSet(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) == 0 || args[0] == nil {
+		return listFlagSets(desc), nil
+	}
+
+	name, isString := args[0].(string)
+	if !isString {
+		return nil, fmt.Errorf("the flag set name must be a string")
+	}
+
+	// tag the value with existing flag set
+	if len(args) == 2 {
+		if _, isString := args[1].(string); !isString {
+			if !user.HasFlagSet(name) {
+				return nil, fmt.Errorf("there is no flag set named '%s'", name)
+			}
+			return utils.Flags{Bits: utils.ToNumber[uint64](args[1]), Set: name}, nil
+		}
+	}
+
+	if len(args)%2 != 1 {
+		return nil, fmt.Errorf("expected pairs of flag name and value")
+	}
+
+	set := user.FlagSet{Flags: make([]user.Flag, 0, len(args)/2)}
+	for i := 1; i < len(args); i += 2 {
+		flagName, isString := args[i].(string)
+		if !isString {
+			return nil, fmt.Errorf("the flag name must be a string")
+		}
+		for _, f := range set.Flags {
+			if f.Name == flagName {
+				return nil, fmt.Errorf("flag '%s' is defined twice", flagName)
+			}
+		}
+		set.Flags = append(set.Flags, user.Flag{Name: flagName, Value: utils.ToNumber[uint64](args[i+1])})
+	}
+
+	user.SetFlagSet(name, set)
+	return uint64(len(set.Flags)), nil
+}
+
+func listFlagSets(desc *types.Descriptor) uint64 {
+	userFlagSets := user.ListFlagSets()
+	if len(userFlagSets) == 0 {
+		fmt.Fprintf(desc.System.Stdout, "\n\tThere are no flag sets.\n")
+		return 0
+	}
+
+	keysList := make([]string, 0, len(userFlagSets))
+	for key := range userFlagSets {
+		keysList = append(keysList, key)
+	}
+	sort.Strings(keysList)
+
+	fmt.Fprintf(desc.System.Stdout, "\n\tFlag sets:\n")
+	for _, key := range keysList {
+		fmt.Fprintf(desc.System.Stdout, "\t\t%s:\n", key)
+		for _, f := range userFlagSets[key].Flags {
+			fmt.Fprintf(desc.System.Stdout, "\t\t\t%-12s0x%X\n", f.Name, f.Value)
+		}
+	}
+
+	return uint64(len(userFlagSets))
+}
+
+func RmFlagSet(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	removedSets := 0
+
+	for _, arg := range args {
+		name, isString := arg.(string)
+		if !isString || !user.HasFlagSet(name) {
+			continue
+		}
+		user.DeleteFlagSet(name)
+		removedSets++
+	}
+
+	return uint64(removedSets), nil
+}
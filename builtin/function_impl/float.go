@@ -0,0 +1,135 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Binary floating-point format.
+type floatFormat struct {
+	// Number of exponent bits.
+	expBits uint8
+	// Number of explicitly stored mantissa bits.
+	manBits uint8
+	// The format has no infinities and only the all ones exponent and mantissa is NaN (like FP8 E4M3).
+	noInf bool
+	// Convert a float to the bits of this format, rounding to nearest even is used if nil.
+	encode func(x float64) uint64
+}
+
+var floatFormats = map[string]floatFormat{
+	"f32": {
+		expBits: 8,
+		manBits: 23,
+		encode:  func(x float64) uint64 { return uint64(math.Float32bits(float32(x))) },
+	},
+	"f64": {
+		expBits: 11,
+		manBits: 52,
+		encode:  math.Float64bits,
+	},
+	"f16":  {expBits: 5, manBits: 10},
+	"bf16": {expBits: 8, manBits: 7},
+	"e4m3": {expBits: 4, manBits: 3, noInf: true},
+	"e5m2": {expBits: 5, manBits: 2},
+}
+
+func (f floatFormat) width() uint8 {
+	return 1 + f.expBits + f.manBits
+}
+
+func (f floatFormat) bias() int64 {
+	return int64(1)<<(f.expBits-1) - 1
+}
+
+// Split float bits into sign, biased exponent and mantissa.
+func (f floatFormat) split(bits uint64) (sign, exp, man uint64) {
+	sign = bits >> (f.expBits + f.manBits) & 1
+	exp = bits >> f.manBits & (uint64(1)<<f.expBits - 1)
+	man = bits & (uint64(1)<<f.manBits - 1)
+	return
+}
+
+// Class of the float with given biased exponent and mantissa.
+func (f floatFormat) class(exp, man uint64) string {
+	expMax := uint64(1)<<f.expBits - 1
+	switch {
+	case exp == 0 && man == 0:
+		return "zero"
+	case exp == 0:
+		return "denormal"
+	case f.noInf && exp == expMax && man == uint64(1)<<f.manBits-1:
+		return "nan"
+	case !f.noInf && exp == expMax && man == 0:
+		return "inf"
+	case !f.noInf && exp == expMax:
+		return "nan"
+	}
+	return "normal"
+}
+
+func floatFormatArg(args []interface{}, i int) (string, floatFormat, error) {
+	if len(args) <= i || args[i] == nil {
+		return "f64", floatFormats["f64"], nil
+	}
+	name, isString := args[i].(string)
+	if !isString {
+		return "", floatFormat{}, fmt.Errorf("the float format name must be a string")
+	}
+	format, found := floatFormats[name]
+	if !found {
+		names := make([]string, 0, len(floatFormats))
+		for key := range floatFormats {
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		return "", floatFormat{}, fmt.Errorf("there is no float format named '%s', available formats: %s", name, strings.Join(names, ", "))
+	}
+	return name, format, nil
+}
+
+func F32Bits(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return utils.NewFixedInt(uint64(math.Float32bits(float32(utils.ToNumber[float64](args[0])))), 32, false), nil
+}
+
+func F64Bits(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return utils.NewFixedInt(math.Float64bits(utils.ToNumber[float64](args[0])), 64, false), nil
+}
+
+func BitsF32(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return float64(math.Float32frombits(uint32(utils.ToNumber[uint64](args[0])))), nil
+}
+
+func BitsF64(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	return math.Float64frombits(utils.ToNumber[uint64](args[0])), nil
+}
+
+func FloatParts(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	name, format, err := floatFormatArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	bits := format.bits(utils.ToNumber[float64](args[0]))
+	sign, exp, man := format.split(bits)
+	class := format.class(exp, man)
+
+	unbiased := int64(exp) - format.bias()
+	if class == "denormal" {
+		unbiased = 1 - format.bias()
+	}
+
+	fmt.Fprintf(desc.System.Stdout, "\n\tParts of %s float:\n", name)
+	fmt.Fprintf(desc.System.Stdout, "\t\tbits        0x%0*X\n", (format.width()+3)/4, bits)
+	fmt.Fprintf(desc.System.Stdout, "\t\tsign        %d\n", sign)
+	fmt.Fprintf(desc.System.Stdout, "\t\texponent    0x%X (biased), %d (unbiased)\n", exp, unbiased)
+	fmt.Fprintf(desc.System.Stdout, "\t\tmantissa    0x%0*X\n", (format.manBits+3)/4, man)
+	fmt.Fprintf(desc.System.Stdout, "\t\tclass       %s\n", class)
+
+	return []interface{}{sign, exp, man}, nil
+}
@@ -0,0 +1,318 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Max degree of the polynomial to check for primitivity,
+// as it requires factorization of 2^n-1.
+const maxPrimitiveDegree = 128
+
+// Max number of LFSR steps to print the output bits of.
+const maxLfsrOutput = 256
+
+var (
+	bigOne = big.NewInt(1)
+	bigTwo = big.NewInt(2)
+)
+
+// The degree of the polynomial p over GF(2), -1 for the zero polynomial.
+func polyDegree(p *big.Int) int {
+	return p.BitLen() - 1
+}
+
+// Carry-less product of a and b.
+func polyMul(a, b *big.Int) *big.Int {
+	r := new(big.Int)
+	shifted := new(big.Int)
+	for i := 0; i < b.BitLen(); i++ {
+		if b.Bit(i) != 0 {
+			r.Xor(r, shifted.Lsh(a, uint(i)))
+		}
+	}
+	return r
+}
+
+// Quotient and remainder of the polynomial division of a by m.
+func polyDivMod(a, m *big.Int) (q, r *big.Int) {
+	q = new(big.Int)
+	r = new(big.Int).Set(a)
+	degM := polyDegree(m)
+	shifted := new(big.Int)
+	for deg := polyDegree(r); deg >= degM; deg = polyDegree(r) {
+		q.SetBit(q, deg-degM, 1)
+		r.Xor(r, shifted.Lsh(m, uint(deg-degM)))
+	}
+	return q, r
+}
+
+func polyMulMod(a, b, m *big.Int) *big.Int {
+	_, r := polyDivMod(polyMul(a, b), m)
+	return r
+}
+
+// a^e mod m over GF(2).
+func polyPowMod(a, e, m *big.Int) *big.Int {
+	_, base := polyDivMod(a, m)
+	r := big.NewInt(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = polyMulMod(r, r, m)
+		if e.Bit(i) != 0 {
+			r = polyMulMod(r, base, m)
+		}
+	}
+	return r
+}
+
+func polyGCD(a, b *big.Int) *big.Int {
+	a, b = new(big.Int).Set(a), new(big.Int).Set(b)
+	for b.Sign() != 0 {
+		_, r := polyDivMod(a, b)
+		a, b = b, r
+	}
+	return a
+}
+
+// Is the polynomial p irreducible over GF(2) (Rabin's test).
+func polyIrreducible(p *big.Int) bool {
+	n := polyDegree(p)
+	if n < 1 {
+		return false
+	}
+
+	x := big.NewInt(2)
+	// x^(2^k) mod p for every k from 1 to n
+	powers := make([]*big.Int, n+1)
+	powers[0] = x
+	for k := 1; k <= n; k++ {
+		powers[k] = polyMulMod(powers[k-1], powers[k-1], p)
+	}
+
+	_, xmod := polyDivMod(x, p)
+	if powers[n].Cmp(xmod) != 0 {
+		return false
+	}
+	for _, q := range factorize(big.NewInt(int64(n))) {
+		k := n / int(q.Int64())
+		h := new(big.Int).Xor(powers[k], xmod)
+		if polyGCD(h, p).Cmp(bigOne) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Is the polynomial p primitive over GF(2).
+func polyPrimitive(p *big.Int) (bool, error) {
+	n := polyDegree(p)
+	if n > maxPrimitiveDegree {
+		return false, fmt.Errorf("the polynomial degree must not exceed %d", maxPrimitiveDegree)
+	}
+	if p.Bit(0) == 0 || !polyIrreducible(p) {
+		return false, nil
+	}
+
+	x := big.NewInt(2)
+	order := new(big.Int).Lsh(bigOne, uint(n))
+	order.Sub(order, bigOne)
+	for _, q := range factorize(order) {
+		e := new(big.Int).Quo(order, q)
+		if polyPowMod(x, e, p).Cmp(bigOne) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Find the distinct prime factors of n > 0.
+func factorize(n *big.Int) []*big.Int {
+	factors := make([]*big.Int, 0)
+	n = new(big.Int).Set(n)
+
+	for d := int64(2); d < 1000 && n.Cmp(bigOne) > 0; d++ {
+		bd := big.NewInt(d)
+		if new(big.Int).Mod(n, bd).Sign() != 0 {
+			continue
+		}
+		factors = append(factors, bd)
+		for new(big.Int).Mod(n, bd).Sign() == 0 {
+			n.Quo(n, bd)
+		}
+	}
+
+	var split func(n *big.Int)
+	split = func(n *big.Int) {
+		if n.Cmp(bigOne) <= 0 {
+			return
+		}
+		if n.ProbablyPrime(20) {
+			for _, f := range factors {
+				if f.Cmp(n) == 0 {
+					return
+				}
+			}
+			factors = append(factors, n)
+			return
+		}
+		d := pollardRho(n)
+		split(d)
+		split(new(big.Int).Quo(n, d))
+	}
+	split(n)
+
+	return factors
+}
+
+// Find a non-trivial divisor of the composite n.
+func pollardRho(n *big.Int) *big.Int {
+	for c := int64(1); ; c++ {
+		x, y, d := big.NewInt(2), big.NewInt(2), big.NewInt(1)
+		bc := big.NewInt(c)
+		f := func(v *big.Int) {
+			v.Mul(v, v).Add(v, bc).Mod(v, n)
+		}
+		diff := new(big.Int)
+		for d.Cmp(bigOne) == 0 {
+			f(x)
+			f(y)
+			f(y)
+			d.GCD(nil, nil, diff.Sub(x, y).Abs(diff), n)
+		}
+		if d.Cmp(n) != 0 {
+			return d
+		}
+	}
+}
+
+// The polynomial p in the x^n + ... + 1 form.
+func polyString(p *big.Int) string {
+	if p.Sign() == 0 {
+		return "0"
+	}
+	terms := make([]string, 0)
+	for i := polyDegree(p); i >= 0; i-- {
+		if p.Bit(i) == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			terms = append(terms, "1")
+		case 1:
+			terms = append(terms, "x")
+		default:
+			terms = append(terms, fmt.Sprintf("x^%d", i))
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
+// Get two polynomial arguments, the second must be non-zero if nonZero is true.
+func polyArgs(args []interface{}, nonZero bool) (*big.Int, *big.Int, error) {
+	if len(args) < 2 {
+		return nil, nil, fmt.Errorf("not enough arguments")
+	}
+	b := bitSet(args[1])
+	if nonZero && b.Sign() == 0 {
+		return nil, nil, fmt.Errorf("division by zero polynomial")
+	}
+	return bitSet(args[0]), b, nil
+}
+
+func CarryLessMul(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	a, b, err := polyArgs(args, false)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeBigInt(polyMul(a, b), true), nil
+}
+
+func PolyMod(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	a, m, err := polyArgs(args, true)
+	if err != nil {
+		return nil, err
+	}
+	_, r := polyDivMod(a, m)
+	return utils.NormalizeBigInt(r, true), nil
+}
+
+func PolyDiv(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	a, m, err := polyArgs(args, true)
+	if err != nil {
+		return nil, err
+	}
+	q, _ := polyDivMod(a, m)
+	return utils.NormalizeBigInt(q, true), nil
+}
+
+func PolyGCD(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	a, b, err := polyArgs(args, false)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeBigInt(polyGCD(a, b), true), nil
+}
+
+func PolyIrreducible(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	return polyIrreducible(bitSet(args[0])), nil
+}
+
+func PolyPrimitive(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	return polyPrimitive(bitSet(args[0]))
+}
+
+func PolyString(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 || args[0] == nil {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	return polyString(bitSet(args[0])), nil
+}
+
+// Step the Galois LFSR with the feedback polynomial taps.
+//
+// The register is shifted left, when the bit of the polynomial degree is shifted out
+// the taps are added to the register. So after n steps the state is state*x^n mod taps.
+func Lfsr(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	state, taps := bitSet(args[0]), bitSet(args[1])
+	width := polyDegree(taps)
+	if width < 1 {
+		return nil, fmt.Errorf("the feedback polynomial degree must be at least 1")
+	}
+	steps := utils.ToBigInt(args[2])
+	if steps.Sign() < 0 {
+		return nil, fmt.Errorf("the number of steps must not be negative")
+	}
+	_, state = polyDivMod(state, taps)
+
+	if !steps.IsInt64() || steps.Int64() > maxLfsrOutput {
+		state = polyMulMod(state, polyPowMod(bigTwo, steps, taps), taps)
+		return utils.NormalizeBigInt(state, true), nil
+	}
+
+	output := make([]byte, steps.Int64())
+	for i := range output {
+		output[i] = '0' + byte(state.Bit(width-1))
+		state.Lsh(state, 1)
+		if state.Bit(width) != 0 {
+			state.Xor(state, taps)
+		}
+	}
+	if len(output) > 0 {
+		fmt.Fprintf(desc.System.Stdout, "\n\tOutput bits: %s\n", output)
+	}
+
+	return utils.NormalizeBigInt(state, true), nil
+}
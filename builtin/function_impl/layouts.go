@@ -0,0 +1,191 @@
+package functionimpl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/user"
+	"github.com/dece2183/hexowl/utils"
+)
+
+func layoutNameArg(arg interface{}) (string, user.Layout, error) {
+	name, isString := arg.(string)
+	if !isString {
+		return "", user.Layout{}, fmt.Errorf("the layout name must be a string")
+	}
+	layout, found := user.GetLayout(name)
+	if !found {
+		return "", user.Layout{}, fmt.Errorf("there is no layout named '%s'", name)
+	}
+	return name, layout, nil
+}
+
+// Parse field definition like EN:0 or MODE:3:1.
+func parseLayoutField(arg interface{}) (user.LayoutField, error) {
+	def, isString := arg.(string)
+	if !isString {
+		return user.LayoutField{}, fmt.Errorf("the field definition must be a string like \"NAME:hi:lo\" or \"NAME:bit\"")
+	}
+
+	parts := strings.Split(def, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return user.LayoutField{}, fmt.Errorf("wrong field definition '%s', expected \"NAME:hi:lo\" or \"NAME:bit\"", def)
+	}
+
+	bounds := make([]uint8, len(parts)-1)
+	for i, p := range parts[1:] {
+		b, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+		if err != nil || b > 63 {
+			return user.LayoutField{}, fmt.Errorf("wrong bit index '%s' of field '%s', must be in range [0,63]", p, parts[0])
+		}
+		bounds[i] = uint8(b)
+	}
+
+	f := user.LayoutField{Name: strings.TrimSpace(parts[0]), Hi: bounds[0], Lo: bounds[len(bounds)-1]}
+	if f.Hi < f.Lo {
+		return user.LayoutField{}, fmt.Errorf("the high bit of field '%s' is less than the low bit", f.Name)
+	}
+	return f, nil
+}
+
+func RegDef(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	name, isString := args[0].(string)
+	if !isString {
+		return nil, fmt.Errorf("the layout name must be a string")
+	}
+
+	layout := user.Layout{Fields: make([]user.LayoutField, 0, len(args)-1)}
+	for _, arg := range args[1:] {
+		f, err := parseLayoutField(arg)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := layout.Field(f.Name); found {
+			return nil, fmt.Errorf("field '%s' is defined twice", f.Name)
+		}
+		layout.Fields = append(layout.Fields, f)
+	}
+
+	user.SetLayout(name, layout)
+	return uint64(len(layout.Fields)), nil
+}
+
+func Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	name, layout, err := layoutNameArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	value := utils.ToNumber[uint64](args[1])
+
+	// show fields from the most significant one
+	fields := make([]user.LayoutField, len(layout.Fields))
+	copy(fields, layout.Fields)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Hi > fields[j].Hi
+	})
+
+	nameWidth := 4
+	for _, f := range fields {
+		if len(f.Name) > nameWidth {
+			nameWidth = len(f.Name)
+		}
+	}
+
+	fmt.Fprintf(desc.System.Stdout, "\n\t%s = 0x%X:\n", name, value)
+	for _, f := range fields {
+		bits := fmt.Sprintf("[%d]", f.Hi)
+		if f.Hi != f.Lo {
+			bits = fmt.Sprintf("[%d:%d]", f.Hi, f.Lo)
+		}
+		v := (value & f.Mask()) >> f.Lo
+		fmt.Fprintf(desc.System.Stdout, "\t\t%-*s %-8s %-10d 0x%-8X 0b%0*b\n", nameWidth, f.Name, bits, v, v, f.Width(), v)
+	}
+
+	result := make([]interface{}, len(layout.Fields))
+	for i, f := range layout.Fields {
+		result[i] = (value & f.Mask()) >> f.Lo
+	}
+	return result, nil
+}
+
+func Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	name, layout, err := layoutNameArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args)%2 != 1 {
+		return nil, fmt.Errorf("expected pairs of field name and value")
+	}
+
+	var value uint64
+	for i := 1; i < len(args); i += 2 {
+		fieldName, isString := args[i].(string)
+		if !isString {
+			return nil, fmt.Errorf("the field name must be a string")
+		}
+		f, found := layout.Field(fieldName)
+		if !found {
+			return nil, fmt.Errorf("there is no field named '%s' in layout '%s'", fieldName, name)
+		}
+		v := utils.ToNumber[uint64](args[i+1])
+		if f.Width() < 64 && v>>f.Width() != 0 {
+			return nil, fmt.Errorf("value %v doesn't fit into %d bits of field '%s'", args[i+1], f.Width(), fieldName)
+		}
+		value = value&^f.Mask() | v<<f.Lo
+	}
+
+	return value, nil
+}
+
+func Layouts(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	userLayouts := user.ListLayouts()
+	if len(userLayouts) == 0 {
+		fmt.Fprintf(desc.System.Stdout, "\n\tThere are no register layouts.\n")
+		return uint64(0), nil
+	}
+
+	keysList := make([]string, 0, len(userLayouts))
+	for key := range userLayouts {
+		keysList = append(keysList, key)
+	}
+	sort.Strings(keysList)
+
+	fmt.Fprintf(desc.System.Stdout, "\n\tRegister layouts:\n")
+	for _, key := range keysList {
+		fields := make([]string, len(userLayouts[key].Fields))
+		for i, f := range userLayouts[key].Fields {
+			fields[i] = f.String()
+		}
+		fmt.Fprintf(desc.System.Stdout, "\t\t%s: %s\n", key, strings.Join(fields, ", "))
+	}
+
+	return uint64(len(userLayouts)), nil
+}
+
+func RmLayout(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	removedLayouts := 0
+
+	for _, arg := range args {
+		name, isString := arg.(string)
+		if !isString || !user.HasLayout(name) {
+			continue
+		}
+		user.DeleteLayout(name)
+		removedLayouts++
+	}
+
+	return uint64(removedLayouts), nil
+}
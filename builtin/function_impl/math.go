@@ -3,34 +3,57 @@ package functionimpl
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"math/bits"
+	"math/cmplx"
 	"math/rand"
+	"strings"
 
 	"github.com/dece2183/hexowl/builtin/types"
 	"github.com/dece2183/hexowl/utils"
 )
 
 func Sin(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if utils.IsComplex(args[0]) {
+		return cmplx.Sin(utils.ToComplex(args[0])), nil
+	}
 	return math.Sin(utils.ToNumber[float64](args[0])), nil
 }
 
 func Cos(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if utils.IsComplex(args[0]) {
+		return cmplx.Cos(utils.ToComplex(args[0])), nil
+	}
 	return math.Cos(utils.ToNumber[float64](args[0])), nil
 }
 
 func Asin(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Asin(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || math.Abs(x) > 1 {
+		return cmplx.Asin(utils.ToComplex(args[0])), nil
+	}
+	return math.Asin(x), nil
 }
 
 func Acos(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Acos(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || math.Abs(x) > 1 {
+		return cmplx.Acos(utils.ToComplex(args[0])), nil
+	}
+	return math.Acos(x), nil
 }
 
 func Tan(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if utils.IsComplex(args[0]) {
+		return cmplx.Tan(utils.ToComplex(args[0])), nil
+	}
 	return math.Tan(utils.ToNumber[float64](args[0])), nil
 }
 
 func Atan(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if utils.IsComplex(args[0]) {
+		return cmplx.Atan(utils.ToComplex(args[0])), nil
+	}
 	return math.Atan(utils.ToNumber[float64](args[0])), nil
 }
 
@@ -38,26 +61,50 @@ func Pow(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
 	if len(args) < 2 {
 		return nil, fmt.Errorf("not enough arguments")
 	}
-	return math.Pow(utils.ToNumber[float64](args[0]), utils.ToNumber[float64](args[1])), nil
+	x, y := utils.ToNumber[float64](args[0]), utils.ToNumber[float64](args[1])
+	// negative base with fractional exponent has complex result
+	if utils.IsComplex(args[0]) || utils.IsComplex(args[1]) || x < 0 && y != math.Trunc(y) {
+		return cmplx.Pow(utils.ToComplex(args[0]), utils.ToComplex(args[1])), nil
+	}
+	return math.Pow(x, y), nil
 }
 
 func Sqrt(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Sqrt(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || x < 0 {
+		return cmplx.Sqrt(utils.ToComplex(args[0])), nil
+	}
+	return math.Sqrt(x), nil
 }
 
 func Logn(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Log(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || x < 0 {
+		return cmplx.Log(utils.ToComplex(args[0])), nil
+	}
+	return math.Log(x), nil
 }
 
 func Log2(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Log2(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || x < 0 {
+		return cmplx.Log(utils.ToComplex(args[0])) / math.Ln2, nil
+	}
+	return math.Log2(x), nil
 }
 
 func Log10(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
-	return math.Log10(utils.ToNumber[float64](args[0])), nil
+	x := utils.ToNumber[float64](args[0])
+	if utils.IsComplex(args[0]) || x < 0 {
+		return cmplx.Log10(utils.ToComplex(args[0])), nil
+	}
+	return math.Log10(x), nil
 }
 
 func Exp(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if utils.IsComplex(args[0]) {
+		return cmplx.Exp(utils.ToComplex(args[0])), nil
+	}
 	return math.Exp(utils.ToNumber[float64](args[0])), nil
 }
 
@@ -96,5 +143,19 @@ func Random(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
 }
 
 func Popcount(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if x, ok := args[0].(*big.Int); ok {
+		return utils.BigOnesCount(x), nil
+	}
 	return uint64(bits.OnesCount64(utils.ToNumber[uint64](args[0]))), nil
 }
+
+func Base(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	base := utils.ToNumber[int64](args[1])
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("the base must be in range [2,36]")
+	}
+	return strings.ToUpper(utils.ToBigInt(args[0]).Text(int(base))), nil
+}
@@ -0,0 +1,220 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+type roundingMode int
+
+// Float rounding modes
+const (
+	// Round to nearest, ties to even.
+	ROUND_NEAREST_EVEN roundingMode = iota
+	// Round towards zero.
+	ROUND_TOWARD_ZERO
+	// Round towards positive infinity.
+	ROUND_UP
+	// Round towards negative infinity.
+	ROUND_DOWN
+	// Round to nearest, ties away from zero.
+	ROUND_NEAREST_AWAY
+)
+
+var roundingModes = map[string]roundingMode{
+	"rne": ROUND_NEAREST_EVEN,
+	"rtz": ROUND_TOWARD_ZERO,
+	"rup": ROUND_UP,
+	"rdn": ROUND_DOWN,
+	"rna": ROUND_NEAREST_AWAY,
+}
+
+// Result flags of the float encoding.
+type encodeStatus struct {
+	overflow  bool
+	underflow bool
+}
+
+func roundingModeArg(args []interface{}, i int) (roundingMode, error) {
+	if len(args) <= i || args[i] == nil {
+		return ROUND_NEAREST_EVEN, nil
+	}
+	name, isString := args[i].(string)
+	if !isString {
+		return 0, fmt.Errorf("the rounding mode must be a string")
+	}
+	mode, found := roundingModes[name]
+	if !found {
+		return 0, fmt.Errorf("there is no rounding mode named '%s', available modes: rne, rtz, rup, rdn, rna", name)
+	}
+	return mode, nil
+}
+
+// Round non-negative x to integer, neg is the sign of the original value.
+func (m roundingMode) round(x float64, neg bool) float64 {
+	switch m {
+	case ROUND_TOWARD_ZERO:
+		return math.Trunc(x)
+	case ROUND_UP:
+		if neg {
+			return math.Trunc(x)
+		}
+		return math.Ceil(x)
+	case ROUND_DOWN:
+		if neg {
+			return math.Ceil(x)
+		}
+		return math.Trunc(x)
+	case ROUND_NEAREST_AWAY:
+		return math.Round(x)
+	}
+	return math.RoundToEven(x)
+}
+
+// Does rounding of the value with sign neg go away from zero on overflow.
+func (m roundingMode) overflowsToInf(neg bool) bool {
+	switch m {
+	case ROUND_TOWARD_ZERO:
+		return false
+	case ROUND_UP:
+		return !neg
+	case ROUND_DOWN:
+		return neg
+	}
+	return true
+}
+
+// The bits of the largest finite value.
+func (f floatFormat) maxFinite() uint64 {
+	if f.noInf {
+		return uint64(1)<<(f.expBits+f.manBits) - 2
+	}
+	return (uint64(1)<<f.expBits-1)<<f.manBits - 1
+}
+
+// The bits of the positive infinity or NaN if the format has no infinities.
+func (f floatFormat) inf() uint64 {
+	if f.noInf {
+		return f.nan()
+	}
+	return (uint64(1)<<f.expBits - 1) << f.manBits
+}
+
+// The bits of the positive quiet NaN.
+func (f floatFormat) nan() uint64 {
+	if f.noInf {
+		return uint64(1)<<(f.expBits+f.manBits) - 1
+	}
+	return (uint64(1)<<f.expBits-1)<<f.manBits | uint64(1)<<(f.manBits-1)
+}
+
+// Convert a float to the bits of this format with rounding to nearest even.
+func (f floatFormat) bits(x float64) uint64 {
+	if f.encode != nil {
+		return f.encode(x)
+	}
+	bits, _ := f.encodeRounded(x, ROUND_NEAREST_EVEN)
+	return bits
+}
+
+// Convert a float to the bits of this format with rounding mode m.
+func (f floatFormat) encodeRounded(x float64, m roundingMode) (uint64, encodeStatus) {
+	var status encodeStatus
+
+	neg := math.Signbit(x)
+	var sign uint64
+	if neg {
+		sign = uint64(1) << (f.expBits + f.manBits)
+	}
+
+	a := math.Abs(x)
+	switch {
+	case math.IsNaN(x):
+		return sign | f.nan(), status
+	case math.IsInf(x, 0):
+		return sign | f.inf(), status
+	case a == 0:
+		return sign, status
+	}
+
+	minExp := 1 - int(f.bias())
+	_, exp := math.Frexp(a)
+	exp--
+
+	var bits uint64
+	if exp > int(f.bias())+1 {
+		status.overflow = true
+	} else {
+		if exp < minExp {
+			exp = minExp
+		}
+		// the value in units of the last place
+		q := math.Ldexp(a, int(f.manBits)-exp)
+		n := m.round(q, neg)
+		bits = uint64(exp-minExp)<<f.manBits + uint64(n)
+		status.overflow = bits > f.maxFinite()
+		status.underflow = bits < uint64(1)<<f.manBits && n != q
+	}
+
+	if status.overflow {
+		if m.overflowsToInf(neg) {
+			return sign | f.inf(), status
+		}
+		return sign | f.maxFinite(), status
+	}
+	return sign | bits, status
+}
+
+// Convert the bits of this format to float64.
+func (f floatFormat) decode(bits uint64) float64 {
+	sign, exp, man := f.split(bits)
+
+	var x float64
+	switch f.class(exp, man) {
+	case "nan":
+		return math.NaN()
+	case "inf":
+		x = math.Inf(1)
+	case "zero", "denormal":
+		x = math.Ldexp(float64(man), 1-int(f.bias())-int(f.manBits))
+	default:
+		x = math.Ldexp(float64(man|uint64(1)<<f.manBits), int(exp)-int(f.bias())-int(f.manBits))
+	}
+
+	if sign != 0 {
+		return -x
+	}
+	return x
+}
+
+// Make a function that encodes its argument to the bits of the float format with name.
+func FloatEncode(name string) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	format := floatFormats[name]
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		mode, err := roundingModeArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		x := utils.ToNumber[float64](args[0])
+		bits, status := format.encodeRounded(x, mode)
+		if status.overflow {
+			fmt.Fprintf(desc.System.Stdout, "\n\tOverflow: %g is out of %s range, converted to %g\n", x, name, format.decode(bits))
+		} else if status.underflow {
+			fmt.Fprintf(desc.System.Stdout, "\n\tUnderflow: %g is too small for %s, converted to %g\n", x, name, format.decode(bits))
+		}
+
+		return utils.NewFixedInt(bits, format.width(), false), nil
+	}
+}
+
+// Make a function that decodes the bits of the float format with name.
+func FloatDecode(name string) func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	format := floatFormats[name]
+	return func(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+		return format.decode(utils.ToNumber[uint64](args[0])), nil
+	}
+}
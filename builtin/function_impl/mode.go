@@ -0,0 +1,50 @@
+package functionimpl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+var modeNames = map[string]utils.Mode{
+	"wrap":     utils.M_WRAP,
+	"rational": utils.M_RATIONAL,
+	"bytes":    utils.M_BYTES,
+	"ascii":    utils.M_ASCII,
+}
+
+func Mode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) == 0 || args[0] == nil {
+		fmt.Fprintf(desc.System.Stdout, "\n\tCalculation modes:\n")
+		keysList := make([]string, 0, len(modeNames))
+		for key := range modeNames {
+			keysList = append(keysList, key)
+		}
+		sort.Strings(keysList)
+		for _, key := range keysList {
+			state := "off"
+			if utils.ModeEnabled(modeNames[key]) {
+				state = "on"
+			}
+			fmt.Fprintf(desc.System.Stdout, "\t\t%-12s%s\n", key, state)
+		}
+		return uint64(len(modeNames)), nil
+	}
+
+	name, isString := args[0].(string)
+	if !isString {
+		return nil, fmt.Errorf("the mode name must be a string")
+	}
+	mode, found := modeNames[name]
+	if !found {
+		return nil, fmt.Errorf("there is no mode named '%s'", name)
+	}
+
+	if len(args) > 1 {
+		utils.SetMode(mode, utils.ToBool(args[1]))
+	}
+
+	return utils.ModeEnabled(mode), nil
+}
@@ -0,0 +1,352 @@
+package functionimpl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Max size of the packed struct in bytes.
+const maxStructSize = 1 << 20
+
+// Sizes of the struct format codes like in Python struct module.
+var structCodeSizes = map[byte]int{
+	'x': 1, 'c': 1, 'b': 1, 'B': 1, '?': 1,
+	'h': 2, 'H': 2, 'i': 4, 'I': 4, 'l': 4, 'L': 4, 'q': 8, 'Q': 8,
+	'e': 2, 'f': 4, 'd': 8, 's': 1, 'p': 1,
+}
+
+// Field of the struct format.
+type structField struct {
+	code byte
+	// Repeat count or the length of the string.
+	count int
+}
+
+type structFormat struct {
+	order binary.ByteOrder
+	// Align fields to their size like C compiler does.
+	align  bool
+	fields []structField
+}
+
+// Parse the format string of Python struct module.
+func parseStructFormat(format string) (structFormat, error) {
+	f := structFormat{order: utils.HostByteOrder, align: true}
+
+	begin := 0
+	if len(format) > 0 {
+		switch format[0] {
+		case '@':
+			begin = 1
+		case '=':
+			f.align = false
+			begin = 1
+		case '<':
+			f.order = binary.LittleEndian
+			f.align = false
+			begin = 1
+		case '>', '!':
+			f.order = binary.BigEndian
+			f.align = false
+			begin = 1
+		}
+	}
+
+	count := -1
+	for i := begin; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if count >= 0 {
+				return structFormat{}, fmt.Errorf("repeat count without format code at position %d of '%s'", i, format)
+			}
+		case c >= '0' && c <= '9':
+			if count < 0 {
+				count = 0
+			}
+			count = count*10 + int(c-'0')
+			if count > maxStructSize {
+				return structFormat{}, fmt.Errorf("the repeat count at position %d of '%s' is too large", i, format)
+			}
+		default:
+			if _, ok := structCodeSizes[c]; !ok {
+				return structFormat{}, fmt.Errorf("unknown format code '%c' at position %d of '%s'", c, i, format)
+			}
+			if count < 0 {
+				count = 1
+			}
+			f.fields = append(f.fields, structField{c, count})
+			count = -1
+		}
+	}
+	if count >= 0 {
+		return structFormat{}, fmt.Errorf("repeat count without format code at the end of '%s'", format)
+	}
+	if size := f.size(); size > maxStructSize {
+		return structFormat{}, fmt.Errorf("the struct size %d exceeds the maximum of %d bytes", size, maxStructSize)
+	}
+
+	return f, nil
+}
+
+// Is the format code stored as a string of count bytes.
+func isStringCode(code byte) bool {
+	return code == 's' || code == 'p'
+}
+
+// Padding before the field at the offset.
+func (f structFormat) padding(offset int, field structField) int {
+	size := structCodeSizes[field.code]
+	if !f.align || size == 1 {
+		return 0
+	}
+	return (size - offset%size) % size
+}
+
+// Size of the packed struct in bytes.
+func (f structFormat) size() int {
+	var offset int
+	for _, field := range f.fields {
+		offset += f.padding(offset, field) + structCodeSizes[field.code]*field.count
+	}
+	return offset
+}
+
+// Number of values the struct consists of.
+func (f structFormat) values() int {
+	var n int
+	for _, field := range f.fields {
+		switch {
+		case field.code == 'x':
+		case isStringCode(field.code):
+			n++
+		default:
+			n += field.count
+		}
+	}
+	return n
+}
+
+// Encode the integer value v in size bytes.
+func (f structFormat) packInteger(v interface{}, size int, signed bool) ([]byte, error) {
+	x := utils.ToBigInt(v)
+	limit := new(big.Int).Lsh(bigOne, uint(size*8))
+	low := new(big.Int)
+	if signed {
+		limit.Rsh(limit, 1)
+		low.Neg(limit)
+	}
+	if x.Cmp(low) < 0 || x.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("the value %v is out of range [%s,%s]", v, low, limit.Sub(limit, bigOne))
+	}
+
+	bits := utils.TruncBigInt(x)
+	switch size {
+	case 1:
+		return []byte{byte(bits)}, nil
+	case 2:
+		return utils.ToByteArrayOrder(uint16(bits), f.order), nil
+	case 4:
+		return utils.ToByteArrayOrder(uint32(bits), f.order), nil
+	}
+	return utils.ToByteArrayOrder(bits, f.order), nil
+}
+
+// Decode the integer from b.
+func (f structFormat) unpackInteger(b []byte, signed bool) utils.FixedInt {
+	var bits uint64
+	switch len(b) {
+	case 1:
+		bits = uint64(b[0])
+	case 2:
+		bits = uint64(utils.FromByteArrayOrder[uint16](b, f.order))
+	case 4:
+		bits = uint64(utils.FromByteArrayOrder[uint32](b, f.order))
+	default:
+		bits = utils.FromByteArrayOrder[uint64](b, f.order)
+	}
+	return utils.NewFixedInt(bits, uint8(len(b)*8), signed)
+}
+
+func (f structFormat) packValue(code byte, v interface{}) ([]byte, error) {
+	switch code {
+	case 'c':
+		if s, ok := v.(string); ok {
+			if len(s) != 1 {
+				return nil, fmt.Errorf("the string \"%s\" must be one character long", s)
+			}
+			return []byte(s), nil
+		}
+		return f.packInteger(v, 1, false)
+	case '?':
+		if utils.ToBool(v) {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case 'e':
+		bits, _ := floatFormats["f16"].encodeRounded(utils.ToNumber[float64](v), ROUND_NEAREST_EVEN)
+		return utils.ToByteArrayOrder(uint16(bits), f.order), nil
+	case 'f':
+		return utils.ToByteArrayOrder(float32(utils.ToNumber[float64](v)), f.order), nil
+	case 'd':
+		return utils.ToByteArrayOrder(utils.ToNumber[float64](v), f.order), nil
+	}
+	return f.packInteger(v, structCodeSizes[code], code >= 'a' && code <= 'z')
+}
+
+func (f structFormat) unpackValue(code byte, b []byte) interface{} {
+	switch code {
+	case 'c':
+		return string(b)
+	case '?':
+		return b[0] != 0
+	case 'e':
+		return floatFormats["f16"].decode(uint64(utils.FromByteArrayOrder[uint16](b, f.order)))
+	case 'f':
+		return float64(utils.FromByteArrayOrder[float32](b, f.order))
+	case 'd':
+		return utils.FromByteArrayOrder[float64](b, f.order)
+	}
+	return f.unpackInteger(b, code >= 'a' && code <= 'z')
+}
+
+// Pack the string or byte array value to count bytes, 'p' strings start with the length byte.
+func packString(code byte, v interface{}, count int) ([]byte, error) {
+	var s []byte
+	switch x := v.(type) {
+	case string:
+		s = []byte(x)
+	case []byte:
+		s = x
+	default:
+		return nil, fmt.Errorf("the value %v for '%c' format must be a string or a byte array", v, code)
+	}
+	b := make([]byte, count)
+	if code == 'p' {
+		if count == 0 {
+			return b, nil
+		}
+		// the length byte can't describe more than 255 bytes
+		if len(s) > 255 {
+			s = s[:255]
+		}
+		b[0] = byte(copy(b[1:], s))
+		return b, nil
+	}
+	copy(b, s)
+	return b, nil
+}
+
+func unpackString(code byte, b []byte) string {
+	if code == 'p' {
+		if len(b) == 0 {
+			return ""
+		}
+		n := int(b[0])
+		if n > len(b)-1 {
+			n = len(b) - 1
+		}
+		return string(b[1 : n+1])
+	}
+	return string(b)
+}
+
+func formatArg(args []interface{}) (structFormat, error) {
+	if len(args) < 1 {
+		return structFormat{}, fmt.Errorf("not enough arguments")
+	}
+	format, ok := args[0].(string)
+	if !ok {
+		return structFormat{}, fmt.Errorf("the format must be a string like \"<IHB\"")
+	}
+	return parseStructFormat(format)
+}
+
+func Pack(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	f, err := formatArg(args)
+	if err != nil {
+		return nil, err
+	}
+	values := args[1:]
+	if len(values) == 1 && values[0] == nil {
+		values = values[:0]
+	}
+	if len(values) != f.values() {
+		return nil, fmt.Errorf("the format requires %d values, got %d", f.values(), len(values))
+	}
+
+	data := make([]byte, 0, f.size())
+	next := 0
+	for _, field := range f.fields {
+		data = append(data, make([]byte, f.padding(len(data), field))...)
+		switch {
+		case field.code == 'x':
+			data = append(data, make([]byte, field.count)...)
+		case isStringCode(field.code):
+			b, err := packString(field.code, values[next], field.count)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, b...)
+			next++
+		default:
+			for i := 0; i < field.count; i++ {
+				b, err := f.packValue(field.code, values[next])
+				if err != nil {
+					return nil, fmt.Errorf("unable to pack value %d: %s", next+1, err)
+				}
+				data = append(data, b...)
+				next++
+			}
+		}
+	}
+
+	return data, nil
+}
+
+func Unpack(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	f, err := formatArg(args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := bytesArg(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != f.size() {
+		return nil, fmt.Errorf("the format requires %d bytes, got %d", f.size(), len(data))
+	}
+
+	result := make([]interface{}, 0, f.values())
+	offset := 0
+	for _, field := range f.fields {
+		offset += f.padding(offset, field)
+		switch {
+		case field.code == 'x':
+			offset += field.count
+		case isStringCode(field.code):
+			result = append(result, unpackString(field.code, data[offset:offset+field.count]))
+			offset += field.count
+		default:
+			size := structCodeSizes[field.code]
+			for i := 0; i < field.count; i++ {
+				result = append(result, f.unpackValue(field.code, data[offset:offset+size]))
+				offset += size
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func CalcSize(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	f, err := formatArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return uint64(f.size()), nil
+}
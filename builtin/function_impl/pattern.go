@@ -0,0 +1,77 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Default and max number of values listed by patlist.
+const (
+	defaultPatternLimit = 256
+	maxPatternLimit     = 65536
+)
+
+func patternArg(args []interface{}, i int) (utils.Pattern, error) {
+	if len(args) <= i {
+		return utils.Pattern{}, fmt.Errorf("not enough arguments")
+	}
+	p, ok := args[i].(utils.Pattern)
+	if !ok {
+		return utils.Pattern{}, fmt.Errorf("the argument %d must be a binary pattern like 0b1x0x", i+1)
+	}
+	return p, nil
+}
+
+func Match(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	p, err := patternArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return p.Match(args[0]), nil
+}
+
+func Capture(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	p, err := patternArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Match(args[0]) {
+		return nil, fmt.Errorf("the value %v doesn't match the pattern %s", args[0], p)
+	}
+	return extract(utils.ToFixedInt(args[0], 64, false).Bits, p.Wildcards()), nil
+}
+
+func PatternList(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	p, err := patternArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	limit := uint64(defaultPatternLimit)
+	if len(args) > 1 {
+		l := utils.ToNumber[int64](args[1])
+		if l < 1 || l > maxPatternLimit {
+			return nil, fmt.Errorf("the limit must be in range [1,%d]", maxPatternLimit)
+		}
+		limit = uint64(l)
+	}
+
+	wildcards := p.Wildcards()
+	total := new(big.Int).Lsh(bigOne, uint(bits.OnesCount64(wildcards)))
+	count := limit
+	if total.IsUint64() && total.Uint64() <= limit {
+		count = total.Uint64()
+	} else {
+		fmt.Fprintf(desc.System.Stdout, "\n\tShowing the first %d of %d matching values\n", limit, total)
+	}
+
+	result := make([]interface{}, count)
+	for i := range result {
+		result[i] = p.Bits | deposit(uint64(i), wildcards)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package functionimpl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// The default number of continued fraction terms.
+const cfracDefaultTerms = 16
+
+// Convert the argument to *big.Rat, NaN and infinities are rejected.
+func ratArg(x interface{}) (*big.Rat, error) {
+	if !utils.IsFinite(x) {
+		return nil, fmt.Errorf("unable to convert %v to a rational number", x)
+	}
+	return utils.ToBigRat(x), nil
+}
+
+func Rational(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, err := ratArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 1 {
+		y, err := ratArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("the denominator must not be zero")
+		}
+		x.Quo(x, y)
+	}
+	return x, nil
+}
+
+func Numerator(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, err := ratArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeBigInt(new(big.Int).Set(x.Num()), false), nil
+}
+
+func Denominator(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	x, err := ratArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return utils.NormalizeBigInt(new(big.Int).Set(x.Denom()), false), nil
+}
+
+func ContinuedFraction(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	terms := cfracDefaultTerms
+	if len(args) > 1 {
+		terms = int(utils.ToNumber[int64](args[1]))
+		if terms < 1 {
+			return nil, fmt.Errorf("the number of terms must be positive")
+		}
+	}
+
+	x, err := ratArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, 0, terms)
+
+	for len(result) < terms {
+		a := new(big.Int).Div(x.Num(), x.Denom())
+		result = append(result, utils.NormalizeBigInt(a, false))
+
+		x.Sub(x, new(big.Rat).SetInt(a))
+		if x.Sign() == 0 {
+			break
+		}
+		x.Inv(x)
+	}
+
+	return result, nil
+}
+
+func RationalApprox(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+
+	maxDen := utils.ToBigInt(args[1])
+	if maxDen.Sign() <= 0 {
+		return nil, fmt.Errorf("the denominator limit must be positive")
+	}
+
+	target, err := ratArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Rat).Set(target)
+
+	// convergents p0/q0 and p1/q1
+	p0, q0 := big.NewInt(0), big.NewInt(1)
+	p1, q1 := big.NewInt(1), big.NewInt(0)
+
+	for {
+		a := new(big.Int).Div(x.Num(), x.Denom())
+		q2 := new(big.Int).Add(q0, new(big.Int).Mul(a, q1))
+		if q2.Cmp(maxDen) > 0 {
+			break
+		}
+
+		p2 := new(big.Int).Add(p0, new(big.Int).Mul(a, p1))
+		p0, q0, p1, q1 = p1, q1, p2, q2
+
+		x.Sub(x, new(big.Rat).SetInt(a))
+		if x.Sign() == 0 {
+			return utils.NormalizeBigRat(new(big.Rat).SetFrac(p1, q1)), nil
+		}
+		x.Inv(x)
+	}
+
+	// the best semiconvergent under the limit
+	k := new(big.Int).Quo(new(big.Int).Sub(maxDen, q0), q1)
+	semi := new(big.Rat).SetFrac(
+		new(big.Int).Add(p0, new(big.Int).Mul(k, p1)),
+		new(big.Int).Add(q0, new(big.Int).Mul(k, q1)),
+	)
+	conv := new(big.Rat).SetFrac(p1, q1)
+
+	semiErr := new(big.Rat).Abs(new(big.Rat).Sub(semi, target))
+	convErr := new(big.Rat).Abs(new(big.Rat).Sub(conv, target))
+	if semiErr.Cmp(convErr) < 0 {
+		return utils.NormalizeBigRat(semi), nil
+	}
+	return utils.NormalizeBigRat(conv), nil
+}
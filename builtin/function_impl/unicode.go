@@ -0,0 +1,118 @@
+package functionimpl
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/dece2183/hexowl/builtin/types"
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Get the code points from the strings and numbers of args.
+func runesArg(args []interface{}) ([]rune, error) {
+	runes := make([]rune, 0, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			continue
+		case string:
+			runes = append(runes, []rune(v)...)
+		default:
+			r := utils.ToNumber[int64](v)
+			if r < 0 || r > utf8.MaxRune || !utf8.ValidRune(rune(r)) {
+				return nil, fmt.Errorf("the value %v at position %d is not a valid Unicode code point", v, i)
+			}
+			runes = append(runes, rune(r))
+		}
+	}
+	if len(runes) == 0 {
+		return nil, fmt.Errorf("not enough arguments")
+	}
+	return runes, nil
+}
+
+func Ord(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	if len(args) > 0 && args[0] != nil && utils.IsInteger(args[0]) {
+		// code point of the character literal
+		runes, err := runesArg(args[:1])
+		if err != nil {
+			return nil, err
+		}
+		return int64(runes[0]), nil
+	}
+
+	s, err := textArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) {
+		return nil, fmt.Errorf("expected a string of one character, but got %d bytes", len(s))
+	}
+	if r == utf8.RuneError {
+		return nil, fmt.Errorf("the string is not valid UTF-8")
+	}
+	return int64(r), nil
+}
+
+func Chr(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	runes, err := runesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return string(runes), nil
+}
+
+func Utf8Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	runes, err := runesArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(string(runes)), nil
+}
+
+func Utf8Decode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	data, err := bytesArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := make([]interface{}, 0, len(data))
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return nil, fmt.Errorf("invalid UTF-8 sequence at position %d", i)
+		}
+		runes = append(runes, int64(r))
+		i += size
+	}
+	return runes, nil
+}
+
+func Utf16Encode(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
+	args, order, hasOrder := optionArg(args)
+	var littleEndian bool
+	if hasOrder {
+		var err error
+		littleEndian, err = byteOrderArg([]interface{}{order}, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	runes, err := runesArg(args)
+	if err != nil {
+		return nil, err
+	}
+
+	units := utf16.Encode(runes)
+	data := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		if littleEndian {
+			data = append(data, byte(u), byte(u>>8))
+		} else {
+			data = append(data, byte(u>>8), byte(u))
+		}
+	}
+	return data, nil
+}
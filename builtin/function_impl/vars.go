@@ -7,6 +7,7 @@ import (
 	"github.com/dece2183/hexowl/builtin/types"
 	"github.com/dece2183/hexowl/input/syntax"
 	"github.com/dece2183/hexowl/user"
+	"github.com/dece2183/hexowl/utils"
 )
 
 func Vars(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
@@ -24,6 +25,10 @@ func Vars(desc *types.Descriptor, args ...interface{}) (interface{}, error) {
 			var outstr string
 			if str, isStr := userVars[key].(string); isStr {
 				outstr = fmt.Sprintf("\t\t[%s] = \"%s\"\n", key, str)
+			} else if data, isBytes := userVars[key].([]byte); isBytes {
+				outstr = fmt.Sprintf("\t\t[%s] = %s\n", key, utils.FormatBytes(data))
+			} else if flags, isFlags := userVars[key].(utils.Flags); isFlags {
+				outstr = fmt.Sprintf("\t\t[%s] = %s (%s)\n", key, user.FormatFlags(flags), flags)
 			} else {
 				outstr = fmt.Sprintf("\t\t[%s] = %v\n", key, userVars[key])
 			}
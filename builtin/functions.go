@@ -1,6 +1,7 @@
 package builtin
 
 import (
+	"encoding/binary"
 	"io"
 
 	impl "github.com/dece2183/hexowl/builtin/function_impl"
@@ -68,6 +69,31 @@ var functions = types.FunctionMap{
 		Desc: "The decimal logarithm of x",
 		Exec: impl.Log10,
 	},
+	"abs": types.Func{
+		Args: "(x)",
+		Desc: "The absolute value (modulus) of x",
+		Exec: impl.Abs,
+	},
+	"arg": types.Func{
+		Args: "(z)",
+		Desc: "The argument (phase) of the complex number z in radians",
+		Exec: impl.Arg,
+	},
+	"conj": types.Func{
+		Args: "(z)",
+		Desc: "The complex conjugate of z",
+		Exec: impl.Conj,
+	},
+	"re": types.Func{
+		Args: "(z)",
+		Desc: "The real part of the complex number z",
+		Exec: impl.Real,
+	},
+	"im": types.Func{
+		Args: "(z)",
+		Desc: "The imaginary part of the complex number z",
+		Exec: impl.Imag,
+	},
 	"round": types.Func{
 		Args: "(x)",
 		Desc: "The nearest integer, rounding half away from zero",
@@ -93,6 +119,551 @@ var functions = types.FunctionMap{
 		Desc: "The number of one bits (\"population count\") in x",
 		Exec: impl.Popcount,
 	},
+	"f32bits": types.Func{
+		Args: "(x)",
+		Desc: "The IEEE 754 single precision bits of x",
+		Exec: impl.F32Bits,
+	},
+	"f64bits": types.Func{
+		Args: "(x)",
+		Desc: "The IEEE 754 double precision bits of x",
+		Exec: impl.F64Bits,
+	},
+	"bitsf32": types.Func{
+		Args: "(x)",
+		Desc: "The float from IEEE 754 single precision bits x",
+		Exec: impl.BitsF32,
+	},
+	"bitsf64": types.Func{
+		Args: "(x)",
+		Desc: "The float from IEEE 754 double precision bits x",
+		Exec: impl.BitsF64,
+	},
+	"tof16": types.Func{
+		Args: "(x,round)",
+		Desc: "The IEEE 754 half precision bits of x rounded with mode rne, rtz, rup, rdn or rna",
+		Exec: impl.FloatEncode("f16"),
+	},
+	"fromf16": types.Func{
+		Args: "(x)",
+		Desc: "The float from IEEE 754 half precision bits x",
+		Exec: impl.FloatDecode("f16"),
+	},
+	"tobf16": types.Func{
+		Args: "(x,round)",
+		Desc: "The bfloat16 bits of x rounded with mode rne, rtz, rup, rdn or rna",
+		Exec: impl.FloatEncode("bf16"),
+	},
+	"frombf16": types.Func{
+		Args: "(x)",
+		Desc: "The float from bfloat16 bits x",
+		Exec: impl.FloatDecode("bf16"),
+	},
+	"toe4m3": types.Func{
+		Args: "(x,round)",
+		Desc: "The FP8 E4M3 bits of x rounded with mode rne, rtz, rup, rdn or rna",
+		Exec: impl.FloatEncode("e4m3"),
+	},
+	"frome4m3": types.Func{
+		Args: "(x)",
+		Desc: "The float from FP8 E4M3 bits x",
+		Exec: impl.FloatDecode("e4m3"),
+	},
+	"toe5m2": types.Func{
+		Args: "(x,round)",
+		Desc: "The FP8 E5M2 bits of x rounded with mode rne, rtz, rup, rdn or rna",
+		Exec: impl.FloatEncode("e5m2"),
+	},
+	"frome5m2": types.Func{
+		Args: "(x)",
+		Desc: "The float from FP8 E5M2 bits x",
+		Exec: impl.FloatDecode("e5m2"),
+	},
+	"fparts": types.Func{
+		Args: "(x,format)",
+		Desc: "Show the sign, exponent and mantissa of x in float format (\"f64\" by default)",
+		Exec: impl.FloatParts,
+	},
+	"base": types.Func{
+		Args: "(x,n)",
+		Desc: "The digits of integer x in base n from 2 to 36",
+		Exec: impl.Base,
+	},
+	"mode": types.Func{
+		Args: "(name,on)",
+		Desc: "Enable or disable calculation mode, list modes if no arguments are passed",
+		Exec: impl.Mode,
+	},
+	"u8": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to unsigned 8-bit integer",
+		Exec: impl.FixedIntCast(8, false),
+	},
+	"u16": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to unsigned 16-bit integer",
+		Exec: impl.FixedIntCast(16, false),
+	},
+	"u32": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to unsigned 32-bit integer",
+		Exec: impl.FixedIntCast(32, false),
+	},
+	"u64": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to unsigned 64-bit integer",
+		Exec: impl.FixedIntCast(64, false),
+	},
+	"i8": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to signed 8-bit integer",
+		Exec: impl.FixedIntCast(8, true),
+	},
+	"i16": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to signed 16-bit integer",
+		Exec: impl.FixedIntCast(16, true),
+	},
+	"i32": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to signed 32-bit integer",
+		Exec: impl.FixedIntCast(32, true),
+	},
+	"i64": types.Func{
+		Args: "(x)",
+		Desc: "Convert x to signed 64-bit integer",
+		Exec: impl.FixedIntCast(64, true),
+	},
+	"toq": types.Func{
+		Args: "(x,format)",
+		Desc: "The fixed-point representation of x in Q-format (\"Q15\", \"Q8.8\", \"UQ16.16\") with rounding and saturation",
+		Exec: impl.ToQ,
+	},
+	"fromq": types.Func{
+		Args: "(x,format)",
+		Desc: "The real value of fixed-point x in Q-format",
+		Exec: impl.FromQ,
+	},
+	"qmul": types.Func{
+		Args: "(a,b,format)",
+		Desc: "The fixed-point product of a and b in Q-format",
+		Exec: impl.QMul,
+	},
+	"qdiv": types.Func{
+		Args: "(a,b,format)",
+		Desc: "The fixed-point quotient of a and b in Q-format",
+		Exec: impl.QDiv,
+	},
+	"sext": types.Func{
+		Args: "(x,bits)",
+		Desc: "Sign-extend the lowest bits of x",
+		Exec: impl.SignExtend,
+	},
+	"zext": types.Func{
+		Args: "(x,bits)",
+		Desc: "Zero-extend the lowest bits of x",
+		Exec: impl.ZeroExtend,
+	},
+	"rotl": types.Func{
+		Args: "(x,n,bits)",
+		Desc: "Rotate the lowest bits of x left by n",
+		Exec: impl.Rotate(true),
+	},
+	"rotr": types.Func{
+		Args: "(x,n,bits)",
+		Desc: "Rotate the lowest bits of x right by n",
+		Exec: impl.Rotate(false),
+	},
+	"bswap": types.Func{
+		Args: "(x,bits)",
+		Desc: "Reverse the byte order of the lowest bits of x",
+		Exec: impl.ByteSwap,
+	},
+	"bitrev": types.Func{
+		Args: "(x,bits)",
+		Desc: "Reverse the order of the lowest bits of x",
+		Exec: impl.BitReverse,
+	},
+	"clz": types.Func{
+		Args: "(x,bits)",
+		Desc: "The number of leading zero bits in the lowest bits of x",
+		Exec: impl.LeadingZeros,
+	},
+	"ctz": types.Func{
+		Args: "(x,bits)",
+		Desc: "The number of trailing zero bits in the lowest bits of x",
+		Exec: impl.TrailingZeros,
+	},
+	"ffs": types.Func{
+		Args: "(x,bits)",
+		Desc: "The 1-based index of the least significant one bit of x or 0 if there is none",
+		Exec: impl.FindFirstSet,
+	},
+	"parity": types.Func{
+		Args: "(x,bits)",
+		Desc: "The even parity bit of the lowest bits of x",
+		Exec: impl.Parity,
+	},
+	"oddparity": types.Func{
+		Args: "(x,bits)",
+		Desc: "The odd parity bit of the lowest bits of x",
+		Exec: impl.OddParity,
+	},
+	"ispow2": types.Func{
+		Args: "(x,bits)",
+		Desc: "Is x a power of two",
+		Exec: impl.IsPowerOfTwo,
+	},
+	"nextpow2": types.Func{
+		Args: "(x,bits)",
+		Desc: "The least power of two greater than or equal to x",
+		Exec: impl.NextPowerOfTwo,
+	},
+	"alignup": types.Func{
+		Args: "(x,a,bits)",
+		Desc: "Align x up to the power of two a",
+		Exec: impl.Align(true),
+	},
+	"aligndown": types.Func{
+		Args: "(x,a,bits)",
+		Desc: "Align x down to the power of two a",
+		Exec: impl.Align(false),
+	},
+	"pdep": types.Func{
+		Args: "(x,mask,bits)",
+		Desc: "Deposit the low bits of x to the one bit positions of mask",
+		Exec: impl.Deposit,
+	},
+	"pext": types.Func{
+		Args: "(x,mask,bits)",
+		Desc: "Extract the bits of x at the one bit positions of mask to the low bits",
+		Exec: impl.Extract,
+	},
+	"gray": types.Func{
+		Args: "(x,bits)",
+		Desc: "The Gray code of x",
+		Exec: impl.GrayEncode,
+	},
+	"ungray": types.Func{
+		Args: "(x,bits)",
+		Desc: "Decode x from the Gray code",
+		Exec: impl.GrayDecode,
+	},
+	"bcd": types.Func{
+		Args: "(x,bits)",
+		Desc: "The packed BCD of x",
+		Exec: impl.BCDEncode,
+	},
+	"unbcd": types.Func{
+		Args: "(x,bits)",
+		Desc: "Decode x from the packed BCD",
+		Exec: impl.BCDDecode,
+	},
+	"mask": types.Func{
+		Args: "(hi,lo)",
+		Desc: "The mask with the bits from hi down to lo set",
+		Exec: impl.Mask,
+	},
+	"bitsof": types.Func{
+		Args: "(x)",
+		Desc: "The list of set bit indices of x",
+		Exec: impl.BitsOf,
+	},
+	"frombits": types.Func{
+		Args: "(indices)",
+		Desc: "The value with the bits at indices set",
+		Exec: impl.FromBits,
+	},
+	"bunion": types.Func{
+		Args: "(x,y)",
+		Desc: "The set bit indices of x or y",
+		Exec: impl.BitsUnion,
+	},
+	"binter": types.Func{
+		Args: "(x,y)",
+		Desc: "The set bit indices of both x and y",
+		Exec: impl.BitsIntersection,
+	},
+	"bdiff": types.Func{
+		Args: "(x,y)",
+		Desc: "The set bit indices of x that are not set in y",
+		Exec: impl.BitsDifference,
+	},
+	"match": types.Func{
+		Args: "(x,pattern)",
+		Desc: "Does x match the binary pattern like 0b1x0x",
+		Exec: impl.Match,
+	},
+	"capture": types.Func{
+		Args: "(x,pattern)",
+		Desc: "The don't care bits of x matching the binary pattern",
+		Exec: impl.Capture,
+	},
+	"patlist": types.Func{
+		Args: "(pattern,limit)",
+		Desc: "The list of values matching the binary pattern, up to limit or 256 values",
+		Exec: impl.PatternList,
+	},
+	"clmul": types.Func{
+		Args: "(a,b)",
+		Desc: "The carry-less product of a and b",
+		Exec: impl.CarryLessMul,
+	},
+	"polymod": types.Func{
+		Args: "(a,m)",
+		Desc: "The remainder of GF(2) polynomial a divided by m",
+		Exec: impl.PolyMod,
+	},
+	"polydiv": types.Func{
+		Args: "(a,m)",
+		Desc: "The quotient of GF(2) polynomial a divided by m",
+		Exec: impl.PolyDiv,
+	},
+	"polygcd": types.Func{
+		Args: "(a,b)",
+		Desc: "The greatest common divisor of GF(2) polynomials a and b",
+		Exec: impl.PolyGCD,
+	},
+	"irreducible": types.Func{
+		Args: "(p)",
+		Desc: "Is GF(2) polynomial p irreducible",
+		Exec: impl.PolyIrreducible,
+	},
+	"primitive": types.Func{
+		Args: "(p)",
+		Desc: "Is GF(2) polynomial p primitive",
+		Exec: impl.PolyPrimitive,
+	},
+	"lfsr": types.Func{
+		Args: "(state,taps,steps)",
+		Desc: "The state of Galois LFSR with feedback polynomial taps after steps",
+		Exec: impl.Lfsr,
+	},
+	"polystr": types.Func{
+		Args: "(p)",
+		Desc: "The GF(2) polynomial p in the x^n + ... + 1 form",
+		Exec: impl.PolyString,
+	},
+	"crc": types.Func{
+		Args: "(data,width,poly,init,refin,refout,xorout)",
+		Desc: "The CRC of data with the Rocksoft model parameters or the preset name as the last argument",
+		Exec: impl.Crc,
+	},
+	"crcs": types.Func{
+		Args: "()",
+		Desc: "List CRC presets",
+		Exec: impl.CrcPresets,
+	},
+	"adler32": types.Func{
+		Args: "(data)",
+		Desc: "The Adler-32 checksum of data",
+		Exec: impl.Adler32,
+	},
+	"fletcher16": types.Func{
+		Args: "(data)",
+		Desc: "The Fletcher-16 checksum of data",
+		Exec: impl.Fletcher16,
+	},
+	"fletcher32": types.Func{
+		Args: "(data)",
+		Desc: "The Fletcher-32 checksum of data",
+		Exec: impl.Fletcher32,
+	},
+	"inetsum": types.Func{
+		Args: "(data)",
+		Desc: "The Internet checksum (RFC 1071) of data",
+		Exec: impl.InternetChecksum,
+	},
+	"xor8": types.Func{
+		Args: "(data)",
+		Desc: "The XOR of data bytes",
+		Exec: impl.Xor8,
+	},
+	"sum8": types.Func{
+		Args: "(data)",
+		Desc: "The sum of data bytes modulo 256",
+		Exec: impl.Sum8,
+	},
+	"pack": types.Func{
+		Args: "(format,values)",
+		Desc: "Pack values to bytes according to the format like \"<IHB\" of Python struct module",
+		Exec: impl.Pack,
+	},
+	"unpack": types.Func{
+		Args: "(format,bytes)",
+		Desc: "Unpack bytes to values according to the format like \"<IHB\" of Python struct module",
+		Exec: impl.Unpack,
+	},
+	"calcsize": types.Func{
+		Args: "(format)",
+		Desc: "The size in bytes of the struct with the format like \"<IHB\"",
+		Exec: impl.CalcSize,
+	},
+	"len": types.Func{
+		Args: "(x)",
+		Desc: "The length of byte array, string or array x",
+		Exec: impl.Len,
+	},
+	"tobytes": types.Func{
+		Args: "(x,n,order)",
+		Desc: "The byte array of n bytes of integer x in \"be\" or \"le\" order, or the bytes of string x",
+		Exec: impl.ToBytes,
+	},
+	"frombytes": types.Func{
+		Args: "(x,order,signed)",
+		Desc: "The integer from byte array x in \"be\" or \"le\" order",
+		Exec: impl.FromBytes,
+	},
+	"htole": types.Func{
+		Args: "(x,bits)",
+		Desc: "Convert x from host to little-endian byte order at 16, 32, 64 or 128 bits width",
+		Exec: impl.HostByteOrder(binary.LittleEndian),
+	},
+	"htobe": types.Func{
+		Args: "(x,bits)",
+		Desc: "Convert x from host to big-endian byte order at 16, 32, 64 or 128 bits width",
+		Exec: impl.HostByteOrder(binary.BigEndian),
+	},
+	"letoh": types.Func{
+		Args: "(x,bits)",
+		Desc: "Convert x from little-endian to host byte order at 16, 32, 64 or 128 bits width",
+		Exec: impl.HostByteOrder(binary.LittleEndian),
+	},
+	"betoh": types.Func{
+		Args: "(x,bits)",
+		Desc: "Convert x from big-endian to host byte order at 16, 32, 64 or 128 bits width",
+		Exec: impl.HostByteOrder(binary.BigEndian),
+	},
+	"hexenc": types.Func{
+		Args: "(data,sep)",
+		Desc: "The hex string of data with optional separator sep between bytes",
+		Exec: impl.HexEncode,
+	},
+	"hexdec": types.Func{
+		Args: "(s)",
+		Desc: "The byte array from hex string s",
+		Exec: impl.HexDecode,
+	},
+	"b64enc": types.Func{
+		Args: "(data,variant)",
+		Desc: "The base64 text of data in \"std\", \"url\", \"rawstd\" or \"rawurl\" variant",
+		Exec: impl.Base64Encode,
+	},
+	"b64dec": types.Func{
+		Args: "(s,variant)",
+		Desc: "The byte array from base64 text s, the variant is guessed if not set",
+		Exec: impl.Base64Decode,
+	},
+	"b32enc": types.Func{
+		Args: "(data,variant)",
+		Desc: "The base32 text of data in \"std\", \"hex\", \"rawstd\" or \"rawhex\" variant",
+		Exec: impl.Base32Encode,
+	},
+	"b32dec": types.Func{
+		Args: "(s,variant)",
+		Desc: "The byte array from base32 text s, the variant is guessed if not set",
+		Exec: impl.Base32Decode,
+	},
+	"a85enc": types.Func{
+		Args: "(data)",
+		Desc: "The ascii85 text of data",
+		Exec: impl.Ascii85Encode,
+	},
+	"a85dec": types.Func{
+		Args: "(s)",
+		Desc: "The byte array from ascii85 text s with optional <~ ~> delimiters",
+		Exec: impl.Ascii85Decode,
+	},
+	"urlenc": types.Func{
+		Args: "(data)",
+		Desc: "Percent-encode data except the unreserved characters",
+		Exec: impl.UrlEncode,
+	},
+	"urldec": types.Func{
+		Args: "(s)",
+		Desc: "Decode percent-encoded string s",
+		Exec: impl.UrlDecode,
+	},
+	"qpenc": types.Func{
+		Args: "(data)",
+		Desc: "The quoted-printable text of data",
+		Exec: impl.QuotedPrintableEncode,
+	},
+	"qpdec": types.Func{
+		Args: "(s)",
+		Desc: "Decode quoted-printable text s",
+		Exec: impl.QuotedPrintableDecode,
+	},
+	"ord": types.Func{
+		Args: "(s)",
+		Desc: "The Unicode code point of one character string or char literal s",
+		Exec: impl.Ord,
+	},
+	"chr": types.Func{
+		Args: "(x)",
+		Desc: "The string of Unicode code points x",
+		Exec: impl.Chr,
+	},
+	"utf8enc": types.Func{
+		Args: "(x)",
+		Desc: "The UTF-8 byte array of Unicode code points or strings x",
+		Exec: impl.Utf8Encode,
+	},
+	"utf8dec": types.Func{
+		Args: "(data)",
+		Desc: "The Unicode code points of UTF-8 data",
+		Exec: impl.Utf8Decode,
+	},
+	"utf16enc": types.Func{
+		Args: "(x,order)",
+		Desc: "The UTF-16 byte array of Unicode code points or strings x in \"be\", \"le\" or \"host\" order",
+		Exec: impl.Utf16Encode,
+	},
+	"ham74": types.Func{
+		Args: "(x)",
+		Desc: "The Hamming(7,4) codeword of 4-bit x",
+		Exec: impl.Hamming74,
+	},
+	"ham74dec": types.Func{
+		Args: "(x)",
+		Desc: "Decode Hamming(7,4) codeword x, the result is the data and the syndrome",
+		Exec: impl.Hamming74Decode,
+	},
+	"secded": types.Func{
+		Args: "(x,bits)",
+		Desc: "The SECDED check bits of 8, 16, 32 or 64-bit data x",
+		Exec: impl.Secded,
+	},
+	"secdeddec": types.Func{
+		Args: "(x,check,bits)",
+		Desc: "Decode SECDED data x with check bits, the result is the data and the syndrome",
+		Exec: impl.SecdedDecode,
+	},
+	"rat": types.Func{
+		Args: "(x,y)",
+		Desc: "The exact rational number x or x/y",
+		Exec: impl.Rational,
+	},
+	"num": types.Func{
+		Args: "(x)",
+		Desc: "The numerator of the rational number x",
+		Exec: impl.Numerator,
+	},
+	"den": types.Func{
+		Args: "(x)",
+		Desc: "The denominator of the rational number x",
+		Exec: impl.Denominator,
+	},
+	"cfrac": types.Func{
+		Args: "(x,n)",
+		Desc: "The first n terms of the continued fraction of x",
+		Exec: impl.ContinuedFraction,
+	},
+	"ratapprox": types.Func{
+		Args: "(x,maxden)",
+		Desc: "The best rational approximation of x with denominator not greater than maxden",
+		Exec: impl.RationalApprox,
+	},
 	"vars": types.Func{
 		Args: "()",
 		Desc: "List available variables",
@@ -108,6 +679,46 @@ var functions = types.FunctionMap{
 		Desc: "Delete user defined variables",
 		Exec: impl.ClearVars,
 	},
+	"regdef": types.Func{
+		Args: "(name,fields)",
+		Desc: "Define register layout with name and fields like \"EN:0\" or \"MODE:3:1\"",
+		Exec: impl.RegDef,
+	},
+	"decode": types.Func{
+		Args: "(name,x)",
+		Desc: "Show the fields of x according to register layout with name",
+		Exec: impl.Decode,
+	},
+	"encode": types.Func{
+		Args: "(name,field,value)",
+		Desc: "Build the register value from pairs of field name and value",
+		Exec: impl.Encode,
+	},
+	"layouts": types.Func{
+		Args: "()",
+		Desc: "List register layouts",
+		Exec: impl.Layouts,
+	},
+	"rmlayout": types.Func{
+		Args: "(name)",
+		Desc: "Delete a specific register layout",
+		Exec: impl.RmLayout,
+	},
+	"flags": types.Func{
+		Args: "(name,flag,value)",
+		Desc: "Define flag set with name from pairs of flag name and value, tag a value with the set if only value is passed, list flag sets if no arguments are passed",
+		Exec: impl.FlagSet,
+	},
+	"rmflags": types.Func{
+		Args: "(name)",
+		Desc: "Delete a specific flag set",
+		Exec: impl.RmFlagSet,
+	},
+	"cimport": types.Func{
+		Args: "(path,prefix)",
+		Desc: "Import #define and enum constants from C header as user variables with optional name prefix",
+		Exec: impl.CImport,
+	},
 	"funcs": types.Func{
 		Args: "()",
 		Desc: "List alailable functions",
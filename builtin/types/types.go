@@ -21,6 +21,9 @@ type System struct {
 	// Callback that should open environment file with provided name for read and return it as io.ReadCloser.
 	ReadEnvironment func(name string) (io.ReadCloser, error)
 
+	// Callback that should open file with provided path for read and return it as io.ReadCloser.
+	ReadFile func(path string) (io.ReadCloser, error)
+
 	// Callback that should terminate the program and perform any necessary cleanup.
 	Exit func(errCode int)
 }
@@ -42,4 +45,7 @@ type Descriptor struct {
 	Constants ConstantMap
 	Functions FunctionMap
 	System    System
+
+	// Callback that calculates expression with additional local variables, registered by the operators package.
+	Evaluate func(expr string, localVars map[string]interface{}) (interface{}, error)
 }
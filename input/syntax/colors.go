@@ -10,7 +10,7 @@ import (
 
 const (
 	// Normal text color
-	C_NORMAL = utils.W_STR + 1 + iota
+	C_NORMAL = utils.W_RAWSTR + 1 + iota
 	// Input prediction text color
 	C_PREDICTION
 	// Error text color
@@ -29,6 +29,14 @@ var colors = map[utils.WordType]string{
 	utils.W_FUNC:    ansi.CreateCS(ansi.SGR, 38, 5, 230),
 	utils.W_STR:     ansi.CreateCS(ansi.SGR, 38, 5, 71),
 
+	utils.W_NUM_OCT:   ansi.CreateCS(ansi.SGR, 38, 5, 32),
+	utils.W_NUM_RADIX: ansi.CreateCS(ansi.SGR, 38, 5, 32),
+	utils.W_NUM_HEXF:  ansi.CreateCS(ansi.SGR, 38, 5, 32),
+	utils.W_NUM_PAT:   ansi.CreateCS(ansi.SGR, 38, 5, 32),
+	utils.W_BYTES:     ansi.CreateCS(ansi.SGR, 38, 5, 71),
+	utils.W_CHAR:      ansi.CreateCS(ansi.SGR, 38, 5, 71),
+	utils.W_RAWSTR:    ansi.CreateCS(ansi.SGR, 38, 5, 71),
+
 	C_NORMAL:     ansi.CreateCS(ansi.SGR, 37),
 	C_PREDICTION: ansi.CreateCS(ansi.SGR, 38, 5, 244),
 	C_ERROR:      ansi.CreateCS(ansi.SGR, 38, 2, 209, 84, 84),
@@ -2,8 +2,14 @@ package main
 
 import (
 	"bufio"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"math/big"
+	"math/cmplx"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/dece2183/hexowl/builtin/default_system"
@@ -11,6 +17,7 @@ import (
 	"github.com/dece2183/hexowl/input/syntax"
 	"github.com/dece2183/hexowl/input/terminal"
 	"github.com/dece2183/hexowl/operators"
+	"github.com/dece2183/hexowl/user"
 	"github.com/dece2183/hexowl/utils"
 )
 
@@ -101,22 +108,69 @@ func calculate(words []utils.Word) error {
 				utils.ToNumber[uint64](val),
 				utils.ToNumber[uint64](val),
 			)
-		case int64, uint64:
+		case int64, uint64, *big.Int:
 			resultStr = fmt.Sprintf(
-				"\t%d\r\n\t\t0x%X\r\n\t\t0b%b\r\n",
+				"\t%d\r\n\t\t%s\r\n\t\t%s\r\n",
 				v,
-				utils.ToNumber[uint64](val),
-				utils.ToNumber[uint64](val),
+				formatHex(val),
+				formatBin(val),
+			)
+			resultStr += formatSignedReading(val)
+			resultStr += formatByteOrders(val)
+			resultStr += formatChars(val)
+		case utils.FixedInt:
+			resultStr = fmt.Sprintf(
+				"\t%s\r\n\t\t%s\r\n\t\t%s\r\n",
+				v,
+				formatHex(val),
+				formatBin(val),
+			)
+			resultStr += formatSignedReading(val)
+			resultStr += formatByteOrders(val)
+			resultStr += formatChars(val)
+		case utils.Flags:
+			resultStr = fmt.Sprintf(
+				"\t%s\r\n\t\t%s\r\n\t\t%s\r\n",
+				user.FormatFlags(v),
+				formatHex(val),
+				formatBin(val),
+			)
+			resultStr += formatByteOrders(val)
+		case []byte:
+			resultStr = fmt.Sprintf("\t%d bytes\r\n", len(v))
+			for _, line := range strings.SplitAfter(hex.Dump(v), "\n") {
+				if line != "" {
+					resultStr += "\t\t" + strings.TrimSuffix(line, "\n") + "\r\n"
+				}
+			}
+		case utils.Pattern:
+			resultStr = fmt.Sprintf(
+				"\t%s\r\n\t\tmask:  %s\r\n\t\tvalue: %s\r\n",
+				v,
+				formatHex(v.Mask),
+				formatHex(v.Bits),
+			)
+		case *big.Rat:
+			approx, _ := v.Float64()
+			resultStr = fmt.Sprintf("\t%s\r\n\t\t%s\r\n", v, strconv.FormatFloat(approx, 'g', -1, 64))
+		case complex128:
+			resultStr = fmt.Sprintf(
+				"\t%s\r\n\t\t%s*exp(%si)\r\n\t\t%s∠%s°\r\n",
+				formatComplex(v),
+				strconv.FormatFloat(cmplx.Abs(v), 'g', -1, 64),
+				strconv.FormatFloat(cmplx.Phase(v), 'g', -1, 64),
+				strconv.FormatFloat(cmplx.Abs(v), 'g', -1, 64),
+				strconv.FormatFloat(cmplx.Phase(v)*180/math.Pi, 'g', -1, 64),
 			)
 		case []interface{}:
 			resultStr = fmt.Sprintf("\t%v\r\n", v)
 			if len(v) > 0 {
 				var hstr, bstr string
 				switch v[0].(type) {
-				case float32, float64, int64, uint64:
+				case float32, float64, int64, uint64, *big.Int, utils.FixedInt, utils.Flags:
 					for _, el := range v {
-						hstr += fmt.Sprintf("0x%X ", utils.ToNumber[uint64](el))
-						bstr += fmt.Sprintf("0b%b ", utils.ToNumber[uint64](el))
+						hstr += formatHex(el) + " "
+						bstr += formatBin(el) + " "
 					}
 					resultStr += fmt.Sprintf("\t\t[%s]\r\n", hstr[:len(hstr)-1])
 					resultStr += fmt.Sprintf("\t\t[%s]\r\n", bstr[:len(bstr)-1])
@@ -131,3 +185,124 @@ func calculate(words []utils.Word) error {
 
 	return nil
 }
+
+// Format complex number in the rectangular form without parentheses, like 3+4i.
+func formatComplex(v complex128) string {
+	s := strconv.FormatComplex(v, 'g', -1, 128)
+	return s[1 : len(s)-1]
+}
+
+func formatHex(val interface{}) string {
+	if x, ok := val.(utils.FixedInt); ok {
+		return fmt.Sprintf("0x%0*X", (x.Width+3)/4, x.Bits)
+	}
+	if x, ok := val.(*big.Int); ok {
+		if x.Sign() < 0 {
+			return fmt.Sprintf("-0x%X", new(big.Int).Neg(x))
+		}
+		return fmt.Sprintf("0x%X", x)
+	}
+	return fmt.Sprintf("0x%X", utils.ToNumber[uint64](val))
+}
+
+func formatBin(val interface{}) string {
+	if x, ok := val.(utils.FixedInt); ok {
+		return fmt.Sprintf("0b%0*b", x.Width, x.Bits)
+	}
+	if x, ok := val.(*big.Int); ok {
+		if x.Sign() < 0 {
+			return fmt.Sprintf("-0b%b", new(big.Int).Neg(x))
+		}
+		return fmt.Sprintf("0b%b", x)
+	}
+	return fmt.Sprintf("0b%b", utils.ToNumber[uint64](val))
+}
+
+// Show the value read as two's complement integer of the narrowest common width that fits it.
+//
+// Returns an empty string if such reading doesn't differ from the value itself.
+func formatSignedReading(val interface{}) string {
+	var x uint64
+	switch v := val.(type) {
+	case utils.FixedInt:
+		if v.IsNegative() {
+			return fmt.Sprintf("\t\tas unsigned: %s\r\n", utils.NewFixedInt(v.Bits, v.Width, false))
+		} else if !v.Signed && v.Bits&(uint64(1)<<(v.Width-1)) != 0 {
+			return fmt.Sprintf("\t\tas signed: %s\r\n", utils.NewFixedInt(v.Bits, v.Width, true))
+		}
+		return ""
+	case int64:
+		x = uint64(v)
+	case uint64:
+		x = v
+	default:
+		return ""
+	}
+
+	// readings of the lowest bits at the common widths if any of them is negative
+	var readings []string
+	negative := false
+	for _, width := range []uint8{8, 16, 32, 64} {
+		reading := utils.NewFixedInt(x, width, true)
+		negative = negative || reading.IsNegative()
+		readings = append(readings, reading.String())
+	}
+	if !negative {
+		return ""
+	}
+	return fmt.Sprintf("\t\tas signed: %s\r\n", strings.Join(readings, " "))
+}
+
+// Show the bytes of the integer in little-endian and big-endian order if the bytes mode is enabled.
+//
+// Fixed-width integers use all bytes of their width, other values use the least number of bytes.
+func formatByteOrders(val interface{}) string {
+	if !utils.ModeEnabled(utils.M_BYTES) {
+		return ""
+	}
+
+	var x *big.Int
+	var n int
+	switch v := val.(type) {
+	case utils.FixedInt:
+		x, n = new(big.Int).SetUint64(v.Bits), int(v.Width+7)/8
+	case int64:
+		if v < 0 {
+			x, n = new(big.Int).SetUint64(uint64(v)), 8
+		}
+	case *big.Int:
+		if v.Sign() < 0 {
+			return ""
+		}
+	}
+	if x == nil {
+		x = utils.ToBigInt(val)
+		n = (x.BitLen() + 7) / 8
+		if n == 0 {
+			n = 1
+		}
+	}
+
+	be := x.FillBytes(make([]byte, n))
+	le := make([]byte, n)
+	for i, b := range be {
+		le[n-1-i] = b
+	}
+	return fmt.Sprintf("\t\tLE: % X\r\n\t\tBE: % X\r\n", le, be)
+}
+
+func formatChars(val interface{}) string {
+	if !utils.ModeEnabled(utils.M_ASCII) {
+		return ""
+	}
+
+	x := utils.ToBigInt(val)
+	if x.Sign() <= 0 || x.BitLen() > 64 {
+		return ""
+	}
+	chars, ok := utils.FormatChars(x.Bytes())
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("\t\t%s\r\n", chars)
+}
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/dece2183/hexowl/builtin"
+	"github.com/dece2183/hexowl/builtin/types"
 	"github.com/dece2183/hexowl/operators"
 	"github.com/dece2183/hexowl/utils"
 )
@@ -79,3 +87,737 @@ func TestCalculateExpr(t *testing.T) {
 		t.Errorf("failed to calculate operators, wrong result:\r\n\texpected: %d\r\n\tresult:   %f\r\n", testExprRes, resNum)
 	}
 }
+
+type testCase struct {
+	expr string
+	res  interface{}
+}
+
+// Expected error that contains the message.
+type testError string
+
+type testEnvFile struct {
+	*bytes.Buffer
+}
+
+func (f testEnvFile) Close() error {
+	return nil
+}
+
+var testEnvFiles = make(map[string]*bytes.Buffer)
+
+// Files read by functions like cimport.
+var testFiles = map[string]string{
+	"regs.h": `/* test registers */
+#ifndef REGS_H
+#define REGS_H
+
+#define REG_BASE    0x40000000UL
+#define REG_CTRL    (REG_BASE + 0x04)   // control
+#define REG_SIZE    (1u << 4)
+#define FLAG_MASK   ((uint8_t)0xF0)
+#define MULTI \
+	(REG_SIZE * 2)
+#define HALF        (7 / 2)
+#define NAME        "regs"
+#define MAX(a, b)   ((a) > (b) ? (a) : (b))
+
+enum mode {
+	MODE_OFF,
+	MODE_ON = 5,
+	MODE_AUTO,
+};
+
+#endif
+`,
+	"decls.h": `typedef enum state state_t;
+#define D_ONE 1
+#define D_TWO 2
+struct point { int x; int y; };
+void enumerate(void);
+#define D_THREE 3
+enum color
+{
+	D_RED,
+	D_GREEN = 4
+};
+`,
+	"unsafe.h": `#define U_KEEP   1
+#define U_BAD    clvars()
+#define U_CALL   MAX(1, 2)
+#define U_ASSIGN ci_keep = 5
+#define U_CHAIN  1; clvars()
+#define U_BIT31  (1u << 31)
+#define U_BIG    4000000000UL
+#define U_SLASH  '/' // slash
+#define U_PATH   "a//b"
+#define U_AFTER  (U_SLASH + 1)
+`,
+}
+
+func init() {
+	builtin.SystemInit(types.System{
+		Stdout: io.Discard,
+		WriteEnvironment: func(name string) (io.WriteCloser, error) {
+			testEnvFiles[name] = &bytes.Buffer{}
+			return testEnvFile{testEnvFiles[name]}, nil
+		},
+		ReadEnvironment: func(name string) (io.ReadCloser, error) {
+			f, ok := testEnvFiles[name]
+			if !ok {
+				return nil, fmt.Errorf("environment '%s' not found", name)
+			}
+			return testEnvFile{bytes.NewBuffer(f.Bytes())}, nil
+		},
+		ReadFile: func(path string) (io.ReadCloser, error) {
+			f, ok := testFiles[path]
+			if !ok {
+				return nil, fmt.Errorf("file '%s' not found", path)
+			}
+			return io.NopCloser(strings.NewReader(f)), nil
+		},
+	})
+}
+
+func calculateTestExpr(expr string) (interface{}, error) {
+	localVars := make(map[string]interface{})
+	op, err := operators.Generate(utils.ParsePrompt(expr), localVars)
+	if err != nil {
+		return nil, err
+	}
+	return operators.Calculate(op, localVars)
+}
+
+func testValuesEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case *big.Int:
+		y, ok := b.(*big.Int)
+		return ok && x.Cmp(y) == 0
+	case *big.Rat:
+		y, ok := b.(*big.Rat)
+		return ok && x.Cmp(y) == 0
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !testValuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func runTestCases(t *testing.T, cases []testCase) {
+	t.Helper()
+	for _, c := range cases {
+		res, err := calculateTestExpr(c.expr)
+		if msg, ok := c.res.(testError); ok {
+			if err == nil || !strings.Contains(err.Error(), string(msg)) {
+				t.Errorf("failed to calculate '%s', error mismatch:\r\n\texpected: %s\r\n\tresult:   %v\r\n", c.expr, msg, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("failed to calculate '%s': %s", c.expr, err)
+			continue
+		}
+		if !testValuesEqual(c.res, res) {
+			t.Errorf("failed to calculate '%s', wrong result:\r\n\texpected: %#v\r\n\tresult:   %#v\r\n", c.expr, c.res, res)
+		}
+	}
+}
+
+func testBigInt(s string) *big.Int {
+	x, _ := new(big.Int).SetString(s, 0)
+	return x
+}
+
+func TestIntegerArithmetic(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"3 * 4", int64(12)},
+		{"0xFF - 1", int64(254)},
+		{"0xFFFFFFFFFFFFFFFF - 1", uint64(0xFFFFFFFFFFFFFFFE)},
+		{"0xFFFFFFFFFFFFFFFF - 0xFFFFFFFFFFFFFFFE", uint64(1)},
+		{"7 // 2", int64(3)},
+		{"7 % 3", int64(1)},
+		{"2 ** 10", int64(1024)},
+		{"8 / 2", int64(4)},
+		{"7 / 2", float64(3.5)},
+		{"1.5 * 2", float64(3)},
+		{"2 ** 0.5", math.Sqrt2},
+		{"ci_a = 5; ci_a += 3; ci_a", int64(8)},
+		{"7 // 0", testError("integer division by zero")},
+		{"7 % 0", testError("integer division by zero")},
+		{"0x1_0000_0000_0000_0000 % 0", testError("integer division by zero")},
+		{"rat(7, 2) % 0", testError("integer division by zero")},
+		{"7 % 0.0", math.Inf(1)},
+		{"0 - 0x8000000000000000", int64(math.MinInt64)},
+		{"0x5 - 7", int64(-2)},
+	})
+}
+
+func TestIntegerWraparound(t *testing.T) {
+	utils.SetMode(utils.M_WRAP, true)
+	defer utils.SetMode(utils.M_WRAP, false)
+
+	runTestCases(t, []testCase{
+		{"1 << 70", int64(0)},
+		{"2 ** 64", int64(0)},
+		{"0xFFFFFFFFFFFFFFFF + 1", uint64(0)},
+		{"0x8000000000000000 * 2", uint64(0)},
+		{"3 - 0xFFFFFFFFFFFFFFFF", int64(4)},
+		{"0x1_0000_0000_0000_0000 + 1", testBigInt("0x10000000000000001")},
+	})
+}
+
+func TestBigInt(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"1 << 70", testBigInt("0x400000000000000000")},
+		{"2 ** 100", testBigInt("1267650600228229401496703205376")},
+		{"0xFFFFFFFFFFFFFFFF + 1", testBigInt("0x10000000000000000")},
+		{"99999999999999999999 * 3", testBigInt("299999999999999999997")},
+		{"(1 << 70) >> 70", int64(1)},
+		{"(2 ** 100) % 7", int64(2)},
+		{"0 - 0x8000000000000000 - 1", testBigInt("-9223372036854775809")},
+		{"3 - 0xFFFFFFFFFFFFFFFF", testBigInt("-18446744073709551612")},
+		{"(0 - 2) ** 63", int64(math.MinInt64)},
+		{"(0 - 2) ** 65", testBigInt("-36893488147419103232")},
+		{"1 << 63", uint64(1 << 63)},
+		{"9223372036854775807 + 1", uint64(1 << 63)},
+		{"3 ** 41", testBigInt("36472996377170786403")},
+		{"1 << 100000000", testError("result is too large")},
+	})
+}
+
+func TestFixedInt(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"0xF0u8 + 0x20", utils.NewFixedInt(0x10, 8, false)},
+		{"127i8 + 1", utils.NewFixedInt(0x80, 8, true)},
+		{"0u16 - 1", utils.NewFixedInt(0xFFFF, 16, false)},
+		{"u8(0x1FF)", utils.NewFixedInt(0xFF, 8, false)},
+		{"i32(0xFFFFFFFF)", utils.NewFixedInt(0xFFFFFFFF, 32, true)},
+		{"i64(0-1i8)", utils.NewFixedInt(0xFFFFFFFFFFFFFFFF, 64, true)},
+		{"0x10u8 * 0x10", utils.NewFixedInt(0, 8, false)},
+		{"1u8 + 1u16", utils.NewFixedInt(2, 16, false)},
+		{"1u16 + 1i16", utils.NewFixedInt(2, 16, false)},
+		{"1u8 << 8", utils.NewFixedInt(0, 8, false)},
+		{"0x80i8 >> 7", utils.NewFixedInt(0xFF, 8, true)},
+		{"1u9", testError("unknown integer type suffix")},
+	})
+}
+
+func TestSignedReading(t *testing.T) {
+	readings := []struct {
+		val interface{}
+		res string
+	}{
+		{int64(4), ""},
+		{int64(-5), "\t\tas signed: -5i8 -5i16 -5i32 -5i64\r\n"},
+		{uint64(0xFFFB), "\t\tas signed: -5i8 -5i16 65531i32 65531i64\r\n"},
+		{utils.NewFixedInt(0xFB, 8, false), "\t\tas signed: -5i8\r\n"},
+		{utils.NewFixedInt(0xFFFE, 16, true), "\t\tas unsigned: 65534u16\r\n"},
+		{utils.NewFixedInt(0x7F, 8, false), ""},
+	}
+	for _, r := range readings {
+		if res := formatSignedReading(r.val); res != r.res {
+			t.Errorf("wrong signed reading of %v:\r\n\texpected: %q\r\n\tresult:   %q\r\n", r.val, r.res, res)
+		}
+	}
+
+	runTestCases(t, []testCase{
+		{"sext(0xFB, 8)", int64(-5)},
+		{"sext(0x7F, 8)", int64(127)},
+		{"sext(0x8000, 16)", int64(-32768)},
+		{"zext(0-5, 8)", uint64(251)},
+		{"zext(0-1, 16)", uint64(0xFFFF)},
+	})
+}
+
+func TestRational(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"rat(1,3) + rat(1,6)", big.NewRat(1, 2)},
+		{"rat(0.75)", big.NewRat(3, 4)},
+		{"rat(2,6) == rat(1,3)", true},
+		{"num(rat(2,6))", int64(1)},
+		{"den(rat(2,6))", int64(3)},
+		{"rat(1,3) - rat(1,2)", big.NewRat(-1, 6)},
+		{"rat(1,3) < rat(1,2)", true},
+		{"cfrac(rat(415,93), 10)", []interface{}{int64(4), int64(2), int64(6), int64(7)}},
+		{"ratapprox(pi, 1000)", big.NewRat(355, 113)},
+		{"rat(1,0)", testError("the denominator must not be zero")},
+		{"rat(nan)", testError("unable to convert NaN to a rational number")},
+		{"rat(inf)", testError("unable to convert +Inf to a rational number")},
+		{"rat(1, inf)", testError("unable to convert +Inf to a rational number")},
+		{"den(inf)", testError("unable to convert +Inf to a rational number")},
+		{"cfrac(nan)", testError("unable to convert NaN to a rational number")},
+		{"rat(1,3) + inf", math.Inf(1)},
+	})
+
+	utils.SetMode(utils.M_RATIONAL, true)
+	defer utils.SetMode(utils.M_RATIONAL, false)
+	runTestCases(t, []testCase{
+		{"1/3 + 1/6", big.NewRat(1, 2)},
+		{"2/7 * 7", int64(2)},
+	})
+}
+
+func TestComplex(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"(1+2i) * (3-1i)", complex(5, 5)},
+		{"(1+2i) / (1-1i)", complex(-0.5, 1.5)},
+		{"i * i", complex(-1, 0)},
+		{"sqrt(0-4)", complex(0, 2)},
+		{"sqrt(0-4) + 3", complex(3, 2)},
+		{"abs(3+4i)", float64(5)},
+		{"arg(1i)", math.Pi / 2},
+		{"conj(1+2i)", complex(1, -2)},
+		{"re(1+2i)", float64(1)},
+		{"im(1+2i)", float64(2)},
+		{"(1+2i) == (1+2i)", true},
+		{"(1+2i) < 1", testError("complex numbers can't be ordered")},
+	})
+}
+
+func TestLiterals(t *testing.T) {
+	parsed := []struct {
+		expr  string
+		words []utils.Word
+	}{
+		{"0o755", []utils.Word{{Type: utils.W_NUM_OCT, Literal: "755"}}},
+		{"36#ZZ", []utils.Word{{Type: utils.W_NUM_RADIX, Literal: "36#ZZ"}}},
+		{"0x1.8p-3", []utils.Word{{Type: utils.W_NUM_HEXF, Literal: "1.8p-3"}}},
+		{"0xFFu8", []utils.Word{{Type: utils.W_NUM_HEX, Literal: "FFu8"}}},
+	}
+	for _, p := range parsed {
+		if words := utils.ParsePrompt(p.expr); !reflect.DeepEqual(words, p.words) {
+			t.Errorf("failed to parse prompt '%s':\r\n\texpected: %+v\r\n\tresult:   %+v\r\n", p.expr, p.words, words)
+		}
+	}
+
+	runTestCases(t, []testCase{
+		{"1_000", int64(1000)},
+		{"0o755", uint64(493)},
+		{"36#ZZ", uint64(1295)},
+		{"2#1010", uint64(10)},
+		{"0x1.8p3", float64(12)},
+		{"0x1p-2", float64(0.25)},
+		{"0x10u8", utils.NewFixedInt(16, 8, false)},
+		{"37#1", testError("the radix of literal '37#1' must be in range [2,36]")},
+		{"base(1295, 36)", "ZZ"},
+		{"base(0-10, 2)", "-1010"},
+	})
+}
+
+func TestBitSlice(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"0xABCD[11:4]", uint64(0xBC)},
+		{"0xABCD[15]", uint64(1)},
+		{"[1, 2]", []interface{}{int64(1), int64(2)}},
+		{"bs_reg = 0x1234; bs_reg[15:8] = 0xAB; bs_reg", uint64(0xAB34)},
+		{"bs_reg = 0xFFu8; bs_reg[3:0] = 0; bs_reg", utils.NewFixedInt(0xF0, 8, false)},
+		{"bs_reg = 5; bs_reg[70] = 1; bs_reg", testBigInt("0x400000000000000005")},
+		{"bs_reg = 0u8; bs_reg[10:8] = 7", testError("bit index 10 is out of range of u8")},
+		{"0xABCD[4:11]", testError("the high bit 4 of slice is less than the low bit 11")},
+	})
+}
+
+func TestLayouts(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"regdef(\"LT_CTRL\", \"EN:0\", \"MODE:3:1\", \"DIV:15:8\"); decode(\"LT_CTRL\", 0x1A05)", []interface{}{uint64(1), uint64(2), uint64(26)}},
+		{"encode(\"LT_CTRL\", \"EN\", 1, \"DIV\", 4)", uint64(0x401)},
+		{"encode(\"LT_CTRL\", \"MODE\", 8)", testError("value 8 doesn't fit into 3 bits of field 'MODE'")},
+		{"regdef(\"LT_BAD\", \"A:3:5\")", testError("the high bit of field 'A' is less than the low bit")},
+		{"decode(\"LT_NONE\", 1)", testError("there is no layout named 'LT_NONE'")},
+		{"save(\"test_layouts\"); load(\"test_layouts\")", true},
+		{"encode(\"LT_CTRL\", \"MODE\", 7)", uint64(0xE)},
+	})
+}
+
+func TestCImport(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"cimport(\"regs.h\", \"CI_\")", uint64(9)},
+		{"CI_REG_BASE", uint64(0x40000000)},
+		{"CI_REG_CTRL", uint64(0x40000004)},
+		{"CI_REG_SIZE", uint64(16)},
+		{"CI_FLAG_MASK", int64(0xF0)},
+		{"CI_MULTI", int64(32)},
+		{"CI_HALF", int64(3)},
+		{"CI_MODE_OFF", int64(0)},
+		{"CI_MODE_ON", int64(5)},
+		{"CI_MODE_AUTO", int64(6)},
+		{"CI_NAME", testError("there is no variable named 'CI_NAME'")},
+		{"cimport(\"missing.h\")", testError("file 'missing.h' not found")},
+		{"cimport(\"decls.h\", \"CI_\")", uint64(5)},
+		{"CI_D_ONE", int64(1)},
+		{"CI_D_TWO", int64(2)},
+		{"CI_D_THREE", int64(3)},
+		{"CI_D_RED", int64(0)},
+		{"CI_D_GREEN", int64(4)},
+		{"ci_keep = 7", int64(7)},
+		{"cimport(\"unsafe.h\", \"CI_\")", uint64(5)},
+		{"ci_keep", int64(7)},
+		{"CI_U_KEEP", int64(1)},
+		{"CI_U_BIT31", uint64(0x80000000)},
+		{"CI_U_BIG", uint64(4000000000)},
+		{"CI_U_SLASH", int64('/')},
+		{"CI_U_AFTER", int64('/' + 1)},
+		{"CI_U_BAD", testError("there is no variable named 'CI_U_BAD'")},
+		{"CI_U_CALL", testError("there is no variable named 'CI_U_CALL'")},
+		{"CI_U_ASSIGN", testError("there is no variable named 'CI_U_ASSIGN'")},
+		{"CI_U_CHAIN", testError("there is no variable named 'CI_U_CHAIN'")},
+		{"CI_U_PATH", testError("there is no variable named 'CI_U_PATH'")},
+	})
+}
+
+func TestFlags(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"flags(\"FT\", \"FT_TX\", 0x1, \"FT_RX\", 0x2, \"FT_ERR\", 0x80)", uint64(3)},
+		{"FT_TX | FT_ERR | 0x100", utils.Flags{Bits: 0x181, Set: "FT"}},
+		{"FT_RX & 0x3", utils.Flags{Bits: 0x2, Set: "FT"}},
+		{"flags(\"FT\", 5)", utils.Flags{Bits: 5, Set: "FT"}},
+		{"FT_TX + 0", int64(1)},
+	})
+}
+
+func TestBitManipulation(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"rotl(0x81, 1, 8)", uint64(0x03)},
+		{"rotr(0x81, 1, 8)", uint64(0xC0)},
+		{"bswap(0x1234, 16)", uint64(0x3412)},
+		{"bswap(0x12, 12)", testError("the bit width must be a multiple of 8")},
+		{"bitrev(1, 8)", uint64(0x80)},
+		{"clz(1, 32)", uint64(31)},
+		{"ctz(8)", uint64(3)},
+		{"ffs(0)", uint64(0)},
+		{"ffs(8)", uint64(4)},
+		{"parity(7)", uint64(1)},
+		{"ispow2(64)", true},
+		{"ispow2(0)", false},
+		{"nextpow2(17)", uint64(32)},
+		{"nextpow2(0xFF, 8)", testError("doesn't fit in 8 bits")},
+		{"alignup(13, 8)", uint64(16)},
+		{"aligndown(13, 8)", uint64(8)},
+		{"pdep(0b101, 0xF0)", uint64(0x50)},
+		{"pext(0xA0, 0xF0)", uint64(0x0A)},
+		{"gray(5)", uint64(7)},
+		{"ungray(7)", uint64(5)},
+		{"bcd(1234)", uint64(0x1234)},
+		{"unbcd(0x1234)", uint64(1234)},
+		{"unbcd(0x1A)", testError("wrong BCD digit 0xA at bits [3:0]")},
+	})
+}
+
+func TestGF2Polynomials(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"clmul(0b11, 0b11)", uint64(0b101)},
+		{"clmul(0xFFFFFFFFFFFFFFFF, 2)", testBigInt("0x1FFFFFFFFFFFFFFFE")},
+		{"polymod(0b1101, 0b11)", uint64(1)},
+		{"polydiv(0b1101, 0b11)", uint64(0b100)},
+		{"polymod(5, 0)", testError("division by zero polynomial")},
+		{"polygcd(0b110, 0b11)", uint64(0b11)},
+		{"irreducible(0b1011)", true},
+		{"irreducible(0b101)", false},
+		{"primitive(0b1011)", true},
+		{"primitive(0x11B)", false},
+		{"polystr(0x11B)", "x^8 + x^4 + x^3 + x + 1"},
+		{"lfsr(1, 0b1011, 3)", uint64(0b011)},
+		{"lfsr(1, 0b1011, 1000000)", uint64(0b010)},
+		{"lfsr(1, 1, 3)", testError("the feedback polynomial degree must be at least 1")},
+	})
+}
+
+func TestChecksums(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"crc(\"123456789\", \"CRC-8\")", utils.NewFixedInt(0xF4, 8, false)},
+		{"crc(\"123456789\", \"CRC-16/CCITT-FALSE\")", utils.NewFixedInt(0x29B1, 16, false)},
+		{"crc(\"123456789\", \"CRC-16/MODBUS\")", utils.NewFixedInt(0x4B37, 16, false)},
+		{"crc(\"123456789\", \"CRC-32\")", utils.NewFixedInt(0xCBF43926, 32, false)},
+		{"crc(\"123456789\", \"CRC-32C\")", utils.NewFixedInt(0xE3069283, 32, false)},
+		{"crc(\"123456789\", \"CRC-32/BZIP2\")", utils.NewFixedInt(0xFC891918, 32, false)},
+		{"crc(\"123456789\", \"CRC-64/XZ\")", utils.NewFixedInt(0x995DC9BBDF1939FA, 64, false)},
+		{"crc(\"123456789\", 32, 0x04C11DB7, 0xFFFFFFFF, true, true, 0xFFFFFFFF)", utils.NewFixedInt(0xCBF43926, 32, false)},
+		{"crc(\"\", \"CRC-32\")", utils.NewFixedInt(0, 32, false)},
+		{"crc(\"123456789\", \"FOO\")", testError("unknown CRC preset 'FOO'")},
+		{"adler32(\"Wikipedia\")", utils.NewFixedInt(0x11E60398, 32, false)},
+		{"fletcher16(\"abcde\")", utils.NewFixedInt(0xC8F0, 16, false)},
+		{"fletcher32(\"abcde\")", utils.NewFixedInt(0xF04FC729, 32, false)},
+		{"inetsum(0x00, 0x01, 0xF2, 0x03, 0xF4, 0xF5, 0xF6, 0xF7)", utils.NewFixedInt(0x220D, 16, false)},
+		{"xor8(\"abc\")", utils.NewFixedInt(0x60, 8, false)},
+		{"sum8(\"abc\")", utils.NewFixedInt(0x26, 8, false)},
+		{"xor8(300)", testError("the value 300 at position 0 doesn't fit in a byte")},
+	})
+}
+
+func TestErrorCorrection(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"ham74(0b1011)", uint64(0x55)},
+		{"ham74(16)", testError("the data must fit in 4 bits")},
+		{"ham74dec(ham74(0b1011))", []interface{}{uint64(0b1011), uint64(0)}},
+		{"ham74dec(ham74(0b1011) ^ 0b100)", []interface{}{uint64(0b1011), uint64(3)}},
+		{"secded(0xA5, 8)", uint64(3)},
+		{"secded(1, 12)", testError("the data width must be 8, 16, 32 or 64 bits")},
+		{"secdeddec(0xA5, secded(0xA5, 8), 8)", []interface{}{uint64(0xA5), uint64(0)}},
+		{"secdeddec(0xA5 ^ 0x10, secded(0xA5, 8), 8)", []interface{}{uint64(0xA5), uint64(25)}},
+		{"secdeddec(0xA5 ^ 0x11, secded(0xA5, 8), 8)", []interface{}{uint64(0xB4), uint64(10)}},
+	})
+}
+
+func TestBitIndexLists(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"mask(7, 4)", uint64(0xF0)},
+		{"mask(3)", uint64(0xF)},
+		{"mask(70, 68)", testBigInt("0x700000000000000000")},
+		{"mask(3, 7)", testError("the high bit 3 of mask is less than the low bit 7")},
+		{"bitsof(0xA5)", []interface{}{uint64(0), uint64(2), uint64(5), uint64(7)}},
+		{"frombits(1, 3, 5) | mask(15, 12)", uint64(0xF02A)},
+		{"bunion(0x05, 0x30)", []interface{}{uint64(0), uint64(2), uint64(4), uint64(5)}},
+		{"binter(7, 6)", []interface{}{uint64(1), uint64(2)}},
+		{"bdiff(7, 6)", []interface{}{uint64(0)}},
+		{"bdiff(0x0F, frombits(0, 2))", []interface{}{uint64(1), uint64(3)}},
+		{"binter(bitsof(7), bitsof(6))", testError("expected two masks")},
+	})
+}
+
+func TestPattern(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"0b1x0x", utils.Pattern{Mask: 0xA, Bits: 0x8, Width: 4}},
+		{"0b1x0x == 0b1101", true},
+		{"0b1x0x == 0b1110", false},
+		{"0b1x0x != 0b11101", true},
+		{"match(0b1001, 0b1x0x)", true},
+		{"capture(0b1101, 0b1x0x)", uint64(3)},
+		{"patlist(0b1x0x)", []interface{}{uint64(8), uint64(9), uint64(12), uint64(13)}},
+		{"patlist(0b1x0x, 2)", []interface{}{uint64(8), uint64(9)}},
+		{"patlist(0b1xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx, 0-1)", testError("the limit must be in range")},
+		{"patlist(0b1x0x, 100000)", testError("the limit must be in range")},
+	})
+}
+
+func TestPack(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"pack(\">IHB\", 0x12345678, 0xABCD, 0xEF)", []byte{0x12, 0x34, 0x56, 0x78, 0xAB, 0xCD, 0xEF}},
+		{"pack(\"<hI\", 0-2, 0x12345678)", []byte{0xFE, 0xFF, 0x78, 0x56, 0x34, 0x12}},
+		{"pack(\"@BI\", 1, 2)", []byte{1, 0, 0, 0, 2, 0, 0, 0}},
+		{"pack(\">4s\", \"ab\")", []byte{'a', 'b', 0, 0}},
+		{"pack(\"3p\", \"abcd\")", []byte{2, 'a', 'b'}},
+		{"pack(\"<4s\", b\"DE AD BE EF\")", []byte{0xDE, 0xAD, 0xBE, 0xEF}},
+		{"pack(\"<3pH\", b\"0102\", 5)", []byte{2, 1, 2, 5, 0}},
+		{"pack(\"<4sH\", unpack(\"<4sH\", b\"41 42 43 44 05 00\"))", []byte{0x41, 0x42, 0x43, 0x44, 0x05, 0x00}},
+		{fmt.Sprintf("pack(\"300p\", \"%s\")[0]", strings.Repeat("a", 280)), uint64(255)},
+		{fmt.Sprintf("pack(\"300p\", \"%s\")[256]", strings.Repeat("a", 280)), uint64(0)},
+		{fmt.Sprintf("unpack(\"300p\", pack(\"300p\", \"%s\"))", strings.Repeat("a", 280)), []interface{}{strings.Repeat("a", 255)}},
+		{"pack(\"<4s\", 5)", testError("the value 5 for 's' format must be a string or a byte array")},
+		{"pack(\"<e\", 1.5)", []byte{0x00, 0x3E}},
+		{"unpack(\"<hI\", b\"FE FF 78 56 34 12\")", []interface{}{utils.NewFixedInt(0xFFFE, 16, true), utils.NewFixedInt(0x12345678, 32, false)}},
+		{"unpack(\"<2H\", b\"01 00 02 00\")", []interface{}{utils.NewFixedInt(1, 16, false), utils.NewFixedInt(2, 16, false)}},
+		{"unpack(\"<f\", pack(\"<f\", 0.5))", []interface{}{float64(0.5)}},
+		{"calcsize(\"@BI\")", uint64(8)},
+		{"calcsize(\"<IHB\")", uint64(7)},
+		{"pack(\"B\", 256)", testError("the value 256 is out of range [0,255]")},
+		{"pack(\"<I?\", 1)", testError("the format requires 2 values, got 1")},
+		{"calcsize(\"99999999999999999999x\")", testError("the repeat count at position 6 of '99999999999999999999x' is too large")},
+		{"pack(\"4000000000x\")", testError("is too large")},
+		{"calcsize(\"1000000q\")", testError("the struct size 8000000 exceeds the maximum of 1048576 bytes")},
+	})
+}
+
+func TestBytes(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"b\"DEAD\" + b\"BEEF\"", []byte{0xDE, 0xAD, 0xBE, 0xEF}},
+		{"b\"DE:AD_BE EF\"[1:3]", []byte{0xAD, 0xBE}},
+		{"b\"DE AD BE EF\"[2]", uint64(0xBE)},
+		{"bt_data = b\"00 11 22\"; bt_data[1:2] = b\"AA BB\"; bt_data", []byte{0x00, 0xAA, 0xBB, 0x22}},
+		{"b\"DEAD\" == b\"DE AD\"", true},
+		{"len(b\"01 02 03\")", uint64(3)},
+		{"tobytes(0x1234, 4, \"le\")", []byte{0x34, 0x12, 0, 0}},
+		{"tobytes(\"hi\")", []byte{'h', 'i'}},
+		{"frombytes(b\"FF FE\", \"be\", 1)", utils.NewFixedInt(0xFFFE, 16, true)},
+		{"b\"ABC\"", testError("must have an even number of hex digits")},
+	})
+}
+
+func TestByteOrder(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"htobe(0x1234, 16)", uint64(0x3412)},
+		{"betoh(0x3412, 16)", uint64(0x1234)},
+		{"htole(0x1234, 16)", uint64(0x1234)},
+		{"letoh(0x1234, 32)", uint64(0x1234)},
+		{"htobe(1, 128)", testBigInt("0x01000000000000000000000000000000")},
+		{"htobe(1, 24)", testError("the bit width must be 16, 32, 64 or 128")},
+		{"frombytes(tobytes(0x1234, 4, \"be\"), \"be\")", utils.NewFixedInt(0x1234, 32, false)},
+		{"frombytes(tobytes(0xFFFE, 2, \"le\"), \"le\", true)", utils.NewFixedInt(0xFFFE, 16, true)},
+	})
+}
+
+func TestEncodings(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"hexenc(\"AB\")", "4142"},
+		{"hexenc(\"AB\", \":\")", "41:42"},
+		{"hexdec(\"41:42\")", []byte("AB")},
+		{"hexdec(\"4\")", testError("the hex string must have an even number of digits")},
+		{"b64enc(\"hi?\")", "aGk/"},
+		{"b64enc(\"hi?>\", \"url\")", "aGk_Pg=="},
+		{"b64enc(\"hi\", \"rawstd\")", "aGk"},
+		{"b64dec(\"aGk=\")", []byte("hi")},
+		{"b64dec(\"aGk\")", []byte("hi")},
+		{"b64dec(\"aGk_Pg==\")", []byte("hi?>")},
+		{"hexenc(\"A\", \"B\")", "41"},
+		{"hexenc(\"A\", \"B\", \"C\")", "414243"},
+		{"hexenc(0x41, 0x42, \":\")", "41423A"},
+		{"hexenc(b\"DEAD\", \":\")", "DE:AD"},
+		{"b64enc(\"x\", \"std\")", "eA=="},
+		{"b64enc(\"std\")", "c3Rk"},
+		{"b64enc(\"x\", \"s\", \"td\")", "eHN0ZA=="},
+		{"b64enc(\"x\", \"foo\")", testError("unknown base64 variant 'foo'")},
+		{"b64dec(\"eA\", \"foo\")", testError("unknown base64 variant 'foo'")},
+		{"b32enc(\"hi\")", "NBUQ===="},
+		{"b32enc(\"hi\", \"hex\")", "D1KG===="},
+		{"b32dec(\"NBUQ====\")", []byte("hi")},
+		{"a85enc(\"hello\")", "BOu!rDZ"},
+		{"a85dec(\"<~BOu!rDZ~>\")", []byte("hello")},
+		{"urlenc(\"a b/c\")", "a%20b%2Fc"},
+		{"urldec(\"a%20b\")", "a b"},
+		{"urldec(\"%zz\")", testError("wrong percent-encoded byte at position 0")},
+		{"qpenc(\"caf\\xE9\")", "caf=E9"},
+		{"qpdec(\"caf=E9\")", "caf\xE9"},
+	})
+}
+
+func TestCharsAndEscapes(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"'A'", int64(65)},
+		{"'\\n'", int64(10)},
+		{"'\\''", int64(39)},
+		{"'\\x41'", int64(0x41)},
+		{"'\\u00e9'", int64(0xE9)},
+		{"'é'", int64(0xE9)},
+		{"'RIFF'", int64(0x52494646)},
+		{"'a' + 1", int64(98)},
+		{"''", testError("empty character literal")},
+		{"'ABCDEFGHI'", testError("is longer than 8 bytes")},
+		{"\"a\\tb\\x41\\u00e9\\\"\"", "a\tbA\u00e9\""},
+		{"\"\\101\\0\"", "A\x00"},
+		{"\"C:\\dev\\new\"", "C:\\dev\new"},
+		{"r\"C:\\dev\\foo.h\"", "C:\\dev\\foo.h"},
+		{"\"\\x4\"", testError("must have 2 hex digits")},
+		{"\"\\uD800\"", testError("is not a valid Unicode code point")},
+		{"ord(\"A\")", int64(65)},
+		{"ord('A')", int64(65)},
+		{"ord(\"é\")", int64(0xE9)},
+		{"ord(\"ab\")", testError("expected a string of one character")},
+		{"chr(72, 105, 0x1F600)", "Hi\U0001F600"},
+		{"chr(0xD800)", testError("is not a valid Unicode code point")},
+		{"utf8enc(0x1F600)", []byte{0xF0, 0x9F, 0x98, 0x80}},
+		{"utf8enc(\"é\", 0x41)", []byte{0xC3, 0xA9, 0x41}},
+		{"utf8dec(b\"F0 9F 98 80 41\")", []interface{}{int64(0x1F600), int64(0x41)}},
+		{"utf8dec(b\"41 C3\")", testError("invalid UTF-8 sequence at position 1")},
+		{"utf16enc(0x1F600)", []byte{0xD8, 0x3D, 0xDE, 0x00}},
+		{"utf16enc(\"hi\", \"le\")", []byte{'h', 0, 'i', 0}},
+		{"utf16enc(0xE9, \"le\")", []byte{0xE9, 0}},
+		{"utf16enc(\"a\", \"b\", \"c\")", []byte{0, 'a', 0, 'b', 0, 'c'}},
+		{"utf16enc(\"a\", \"x\")", testError("the byte order must be \"le\", \"be\" or \"host\"")},
+	})
+
+	utils.SetMode(utils.M_ASCII, true)
+	defer utils.SetMode(utils.M_ASCII, false)
+	chars := []struct {
+		val interface{}
+		res string
+	}{
+		{int64(0x52494646), "\t\t'RIFF'\r\n"},
+		{int64(39), "\t\t'\\''\r\n"},
+		{utils.NewFixedInt(0x41, 8, false), "\t\t'A'\r\n"},
+		{int64(0x1F), ""},
+		{int64(-1), ""},
+	}
+	for _, c := range chars {
+		if res := formatChars(c.val); res != c.res {
+			t.Errorf("wrong characters of %v:\r\n\texpected: %q\r\n\tresult:   %q\r\n", c.val, c.res, res)
+		}
+	}
+}
+
+func TestFloatBits(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"f32bits(1.5)", utils.NewFixedInt(0x3FC00000, 32, false)},
+		{"f64bits(1.5)", utils.NewFixedInt(0x3FF8000000000000, 64, false)},
+		{"f32bits(0-0.1)", utils.NewFixedInt(0xBDCCCCCD, 32, false)},
+		{"bitsf32(0x3FC00000)", float64(1.5)},
+		{"bitsf64(0x3FF8000000000000)", float64(1.5)},
+		{"bitsf32(0x7F800000)", math.Inf(1)},
+	})
+}
+
+func TestSmallFloats(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"tof16(1)", utils.NewFixedInt(0x3C00, 16, false)},
+		{"tof16(65504)", utils.NewFixedInt(0x7BFF, 16, false)},
+		{"tof16(65520)", utils.NewFixedInt(0x7C00, 16, false)},
+		{"tof16(1e-8)", utils.NewFixedInt(0, 16, false)},
+		{"tof16(0.1)", utils.NewFixedInt(0x2E66, 16, false)},
+		{"tof16(0.1, \"rtz\")", utils.NewFixedInt(0x2E66, 16, false)},
+		{"tof16(0.1, \"rup\")", utils.NewFixedInt(0x2E67, 16, false)},
+		{"tof16(0-0.1, \"rdn\")", utils.NewFixedInt(0xAE67, 16, false)},
+		{"tof16(1.00048828125)", utils.NewFixedInt(0x3C00, 16, false)},
+		{"tof16(1.00048828125, \"rna\")", utils.NewFixedInt(0x3C01, 16, false)},
+		{"tobf16(1)", utils.NewFixedInt(0x3F80, 16, false)},
+		{"tobf16(3.14159)", utils.NewFixedInt(0x4049, 16, false)},
+		{"tobf16(3.14159, \"rup\")", utils.NewFixedInt(0x404A, 16, false)},
+		{"toe4m3(1)", utils.NewFixedInt(0x38, 8, false)},
+		{"toe4m3(448)", utils.NewFixedInt(0x7E, 8, false)},
+		{"toe4m3(500)", utils.NewFixedInt(0x7F, 8, false)},
+		{"toe5m2(1)", utils.NewFixedInt(0x3C, 8, false)},
+		{"toe5m2(57344)", utils.NewFixedInt(0x7B, 8, false)},
+		{"fromf16(0x3C00)", float64(1)},
+		{"fromf16(0x7C00)", math.Inf(1)},
+		{"frombf16(0x3F80)", float64(1)},
+		{"frome4m3(0x7E)", float64(448)},
+		{"frome5m2(0x7B)", float64(57344)},
+		{"tof16(1, \"xyz\")", testError("there is no rounding mode named 'xyz'")},
+	})
+}
+
+func TestFixedPoint(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"toq(0.5, \"Q15\")", utils.NewFixedInt(0x4000, 16, true)},
+		{"toq(0.1, \"Q15\")", utils.NewFixedInt(0x0CCD, 16, true)},
+		{"toq(1, \"Q15\")", utils.NewFixedInt(0x7FFF, 16, true)},
+		{"toq(0-1, \"Q15\")", utils.NewFixedInt(0x8000, 16, true)},
+		{"toq(0-2, \"Q15\")", utils.NewFixedInt(0x8000, 16, true)},
+		{"toq(0.5, \"Q31\")", utils.NewFixedInt(0x40000000, 32, true)},
+		{"toq(1.5, \"Q8.8\")", utils.NewFixedInt(0x0180, 16, true)},
+		{"toq(1.5, \"UQ16.16\")", utils.NewFixedInt(0x18000, 32, false)},
+		{"toq(0-1, \"UQ8.8\")", utils.NewFixedInt(0, 16, false)},
+		{"fromq(0x4000, \"Q15\")", float64(0.5)},
+		{"fromq(toq(0-1.25, \"Q8.8\"), \"Q8.8\")", float64(-1.25)},
+		{"fromq(qmul(toq(0.5, \"Q15\"), toq(0-0.75, \"Q15\"), \"Q15\"), \"Q15\")", float64(-0.375)},
+		{"qdiv(toq(0.25, \"Q15\"), toq(0.5, \"Q15\"), \"Q15\")", utils.NewFixedInt(0x4000, 16, true)},
+		{"qdiv(toq(0.5, \"Q15\"), toq(0.25, \"Q15\"), \"Q15\")", utils.NewFixedInt(0x7FFF, 16, true)},
+		{"toq(1, \"Q\")", testError("unable to parse Q-format 'Q'")},
+	})
+}
+
+// Save the user variables, drop them and load them back.
+func TestSaveLoadVariables(t *testing.T) {
+	runTestCases(t, []testCase{
+		{"flags(\"SL\", \"SL_A\", 1, \"SL_B\", 2)", uint64(2)},
+		{"sl_int = 0-5; sl_uint = 0xFFFFFFFFFFFFFFFF; sl_big = 99999999999999999999; sl_arr = [1, 99999999999999999999]; sl_fixed = 0-5i16; sl_rat = rat(1,3); sl_complex = 1.5-2i; sl_flags = flags(\"SL\", 3); sl_pat = 0b1x0x; sl_bytes = b\"DE AD BE EF\"; save(\"test_vars\")", true},
+		{"clvars(); load(\"test_vars\")", true},
+		{"sl_int", int64(-5)},
+		{"sl_uint", uint64(0xFFFFFFFFFFFFFFFF)},
+		{"sl_big", testBigInt("99999999999999999999")},
+		{"sl_arr", []interface{}{int64(1), testBigInt("99999999999999999999")}},
+		{"sl_fixed", utils.NewFixedInt(0xFFFB, 16, true)},
+		{"sl_fixed + 1", utils.NewFixedInt(0xFFFC, 16, true)},
+		{"sl_rat + rat(1,6)", big.NewRat(1, 2)},
+		{"sl_complex", complex(1.5, -2)},
+		{"sl_flags", utils.Flags{Bits: 3, Set: "SL"}},
+		{"sl_flags & SL_B", utils.Flags{Bits: 2, Set: "SL"}},
+		{"sl_pat == 0b1101", true},
+		{"sl_bytes", []byte{0xDE, 0xAD, 0xBE, 0xEF}},
+	})
+}
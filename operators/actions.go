@@ -2,8 +2,6 @@ package operators
 
 import (
 	"fmt"
-	"math"
-	"math/bits"
 
 	"github.com/dece2183/hexowl/builtin"
 	"github.com/dece2183/hexowl/user"
@@ -61,64 +59,12 @@ var opActionList = map[operatorType]action{
 		return op.Result, nil
 	},
 
-	OP_DECREMENT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		op.Result = utils.ToNumber[float64](opA) - utils.ToNumber[float64](op.OperandB.Result)
-		return opActionAssign(op, localVars)
-	},
-
-	OP_INCREMENT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		op.Result = utils.ToNumber[float64](opA) + utils.ToNumber[float64](op.OperandB.Result)
-		return opActionAssign(op, localVars)
-	},
-
-	OP_ASSIGNMUL: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		op.Result = utils.ToNumber[float64](opA) * utils.ToNumber[float64](op.OperandB.Result)
-		return opActionAssign(op, localVars)
-	},
-
-	OP_ASSIGNDIV: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		opB := utils.ToNumber[float64](op.OperandB.Result)
-		if opB == 0 {
-			op.Result = math.Inf(int(utils.ToNumber[float64](opA)))
-		} else {
-			op.Result = utils.ToNumber[float64](opA) / opB
-		}
-		return opActionAssign(op, localVars)
-	},
-
-	OP_ASSIGNBITAND: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		op.Result = utils.ToNumber[uint64](opA) & utils.ToNumber[uint64](op.OperandB.Result)
-		return opActionAssign(op, localVars)
-	},
-
-	OP_ASSIGNBITOR: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opA, err := obtainVar(op.OperandA, localVars)
-		if err != nil {
-			return nil, err
-		}
-		op.Result = utils.ToNumber[uint64](opA) | utils.ToNumber[uint64](op.OperandB.Result)
-		return opActionAssign(op, localVars)
-	},
+	OP_DECREMENT:    opActionCompoundAssign(OP_MINUS),
+	OP_INCREMENT:    opActionCompoundAssign(OP_PLUS),
+	OP_ASSIGNMUL:    opActionCompoundAssign(OP_MULTIPLY),
+	OP_ASSIGNDIV:    opActionCompoundAssign(OP_DIVIDE),
+	OP_ASSIGNBITAND: opActionCompoundAssign(OP_BITAND),
+	OP_ASSIGNBITOR:  opActionCompoundAssign(OP_BITOR),
 
 	OP_LOGICNOT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
 		op.Result = !utils.ToBool(op.OperandB.Result)
@@ -135,114 +81,35 @@ var opActionList = map[operatorType]action{
 		return op.Result, nil
 	},
 
-	OP_EQUALITY: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) == utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_NOTEQ: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) != utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_MORE: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) > utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_LESS: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) < utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_MOREEQ: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) >= utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_LESSEQ: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) <= utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_MINUS: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) - utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_PLUS: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) + utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_MULTIPLY: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[float64](op.OperandA.Result) * utils.ToNumber[float64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_DIVIDE: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opB := utils.ToNumber[float64](op.OperandB.Result)
-		if opB == 0 {
-			op.Result = math.Inf(int(utils.ToNumber[float64](op.OperandA.Result)))
-		} else {
-			op.Result = utils.ToNumber[float64](op.OperandA.Result) / opB
-		}
-		return op.Result, nil
-	},
-
-	OP_MODULO: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		opB := utils.ToNumber[int64](op.OperandB.Result)
-		if opB == 0 {
-			op.Result = math.Inf(1)
-		} else {
-			op.Result = utils.ToNumber[int64](op.OperandA.Result) % opB
-		}
-		return op.Result, nil
-	},
-
-	OP_POWER: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = math.Pow(utils.ToNumber[float64](op.OperandA.Result), utils.ToNumber[float64](op.OperandB.Result))
-		return op.Result, nil
-	},
-
-	OP_LEFTSHIFT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) << utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_RIGHTSHIFT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) >> utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_BITOR: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) | utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_BITAND: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) & utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_BITXOR: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) ^ utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_BITCLEAR: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = utils.ToNumber[uint64](op.OperandA.Result) &^ utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
-	},
-
-	OP_POPCNT: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = uint64(bits.OnesCount64(utils.ToNumber[uint64](op.OperandB.Result)))
-		return op.Result, nil
-	},
-
-	OP_BITINVERSE: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
-		op.Result = 0xFFFFFFFFFFFFFFFF ^ utils.ToNumber[uint64](op.OperandB.Result)
-		return op.Result, nil
+	OP_EQUALITY: opActionBinary,
+	OP_NOTEQ:    opActionBinary,
+	OP_MORE:     opActionBinary,
+	OP_LESS:     opActionBinary,
+	OP_MOREEQ:   opActionBinary,
+	OP_LESSEQ:   opActionBinary,
+
+	OP_MINUS:     opActionBinary,
+	OP_PLUS:      opActionBinary,
+	OP_MULTIPLY:  opActionBinary,
+	OP_DIVIDE:    opActionBinary,
+	OP_INTDIVIDE: opActionBinary,
+	OP_MODULO:    opActionBinary,
+	OP_POWER:     opActionBinary,
+
+	OP_LEFTSHIFT:  opActionBinary,
+	OP_RIGHTSHIFT: opActionBinary,
+	OP_BITOR:      opActionBinary,
+	OP_BITAND:     opActionBinary,
+	OP_BITXOR:     opActionBinary,
+	OP_BITCLEAR:   opActionBinary,
+
+	OP_POPCNT:     opActionUnary,
+	OP_BITINVERSE: opActionUnary,
+
+	OP_SLICE: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+		var err error
+		op.Result, err = sliceValue(op.OperandA.Result, op.OperandB.Result)
+		return op.Result, err
 	},
 
 	OP_ENUMERATE: func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
@@ -306,9 +173,40 @@ var opActionList = map[operatorType]action{
 
 func init() {
 	opActionListP = &opActionList
+	builtin.RegisterEvaluator(Evaluate)
+}
+
+func opActionBinary(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+	var err error
+	op.Result, err = calcBinary(op.Type, op.OperandA.Result, op.OperandB.Result)
+	return op.Result, err
+}
+
+func opActionUnary(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+	var err error
+	op.Result, err = calcUnary(op.Type, op.OperandB.Result)
+	return op.Result, err
+}
+
+func opActionCompoundAssign(opType operatorType) action {
+	return func(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+		opA, err := obtainVar(op.OperandA, localVars)
+		if err != nil {
+			return nil, err
+		}
+		op.Result, err = calcBinary(opType, opA, op.OperandB.Result)
+		if err != nil {
+			return nil, err
+		}
+		return opActionAssign(op, localVars)
+	}
 }
 
 func opActionAssign(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+	if op.OperandA.Type == OP_SLICE {
+		return opActionSliceAssign(op, localVars)
+	}
+
 	action, ok := (*opActionListP)[op.OperandA.Type]
 	if ok {
 		return action(op, localVars)
@@ -317,6 +215,21 @@ func opActionAssign(op *Operator, localVars map[string]interface{}) (interface{}
 	}
 }
 
+// Assign op.Result to the bit slice of the variable or the bytes of the byte array.
+func opActionSliceAssign(op *Operator, localVars map[string]interface{}) (interface{}, error) {
+	slice := op.OperandA
+	v, err := obtainVar(slice.OperandA, localVars)
+	if err != nil {
+		return nil, err
+	}
+
+	op.Result, err = assignSlice(v, slice.OperandB.Result, op.Result)
+	if err != nil {
+		return nil, err
+	}
+	return opActionAssign(&Operator{OperandA: slice.OperandA, Result: op.Result}, localVars)
+}
+
 func opDoAction(op *Operator, localVars map[string]interface{}) (interface{}, error) {
 	action, ok := (*opActionListP)[op.Type]
 	if ok {
@@ -330,7 +243,13 @@ func obtainVar(op *Operator, localVars map[string]interface{}) (interface{}, err
 	var v interface{}
 	var ok bool
 
-	if op.Type == OP_LOCALVAR {
+	if op.Type == OP_SLICE {
+		v, err := obtainVar(op.OperandA, localVars)
+		if err != nil {
+			return nil, err
+		}
+		return sliceValue(v, op.OperandB.Result)
+	} else if op.Type == OP_LOCALVAR {
 		v, ok = localVars[op.Result.(string)]
 	} else if op.Type == OP_USERVAR {
 		v, ok = user.GetVariable(op.Result.(string))
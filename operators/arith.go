@@ -0,0 +1,581 @@
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+// The maximum bit length of an arbitrary-precision integer result.
+const maxBigIntBits = 1 << 20
+
+func isFloat(v interface{}) bool {
+	switch v.(type) {
+	case float32, float64:
+		return true
+	}
+	return false
+}
+
+func isBigInt(v interface{}) bool {
+	_, ok := v.(*big.Int)
+	return ok
+}
+
+func isFixedInt(v interface{}) bool {
+	_, ok := v.(utils.FixedInt)
+	return ok
+}
+
+func isSigned(v interface{}) bool {
+	switch n := v.(type) {
+	case int, int64:
+		return true
+	case *big.Int:
+		return n.Sign() < 0
+	case utils.FixedInt:
+		return n.Signed
+	}
+	return false
+}
+
+func useBigInt(a, b interface{}) bool {
+	if isFloat(a) || isFloat(b) {
+		return false
+	}
+	return isBigInt(a) || isBigInt(b)
+}
+
+func isUnsigned(v interface{}) bool {
+	switch n := v.(type) {
+	case byte, uint, uint64, string:
+		return true
+	case utils.FixedInt:
+		return !n.Signed
+	}
+	return false
+}
+
+// Is the result of an integer operation on a and b unsigned.
+//
+// The unsigned operand wins only if its value doesn't fit into int64.
+func intResultUnsigned(a, b interface{}) bool {
+	ua, ub := isUnsigned(a), isUnsigned(b)
+	if ua == ub {
+		return ua
+	} else if ua {
+		return utils.ToNumber[uint64](a) > math.MaxInt64
+	} else {
+		return utils.ToNumber[uint64](b) > math.MaxInt64
+	}
+}
+
+// Split integer v into the sign and the absolute value.
+func intMagnitude(v interface{}) (neg bool, mag uint64) {
+	x := utils.ToNumber[uint64](v)
+	if !isUnsigned(v) && int64(x) < 0 {
+		return true, -x
+	}
+	return false, x
+}
+
+// Calculate the sign and the absolute value of the exact result of a+b, a-b, a*b, a**b or a<<b.
+//
+// The exponent and the shift count must not be negative.
+// ok is false if the absolute value doesn't fit into 64 bits.
+func exactIntOp(op operatorType, a, b interface{}) (neg bool, mag uint64, ok bool) {
+	na, ma := intMagnitude(a)
+	nb, mb := intMagnitude(b)
+
+	switch op {
+	case OP_MINUS:
+		nb = !nb
+		fallthrough
+	case OP_PLUS:
+		if na == nb {
+			var carry uint64
+			mag, carry = bits.Add64(ma, mb, 0)
+			neg, ok = na, carry == 0
+		} else if ma >= mb {
+			neg, mag, ok = na, ma-mb, true
+		} else {
+			neg, mag, ok = nb, mb-ma, true
+		}
+	case OP_MULTIPLY:
+		var hi uint64
+		hi, mag = bits.Mul64(ma, mb)
+		neg, ok = na != nb, hi == 0
+	case OP_POWER:
+		neg, mag, ok = na && mb&1 != 0, 1, true
+		base := ma
+		for exp := mb; exp > 0 && ok; exp >>= 1 {
+			var hi uint64
+			if exp&1 != 0 {
+				hi, mag = bits.Mul64(mag, base)
+				ok = hi == 0
+			}
+			if exp > 1 && ok {
+				hi, base = bits.Mul64(base, base)
+				ok = hi == 0
+			}
+		}
+	case OP_LEFTSHIFT:
+		neg = na
+		ok = ma == 0 || mb < 64 && bits.Len64(ma)+int(mb) <= 64
+		if ok {
+			mag = ma << mb
+		}
+	}
+
+	return neg && mag != 0, mag, ok
+}
+
+// Integer result of operator op that may not fit into 64 bits.
+//
+// r is the result wrapped around modulo 2^64. If the exact result doesn't fit into int64 or uint64
+// it's promoted to arbitrary-precision integer unless the wrap mode is enabled.
+func exactIntResult(op operatorType, a, b interface{}, r uint64, unsigned bool) (interface{}, error) {
+	neg, mag, ok := exactIntOp(op, a, b)
+	if ok && (!neg || mag <= 1<<63) {
+		return intResult(r, !neg && (unsigned || mag > math.MaxInt64)), nil
+	}
+	if utils.ModeEnabled(utils.M_WRAP) {
+		// negative results are signed even if the unsigned operand wins
+		return intResult(r, unsigned && !neg), nil
+	}
+	return calcBigInt(op, utils.ToBigInt(a), utils.ToBigInt(b), unsigned)
+}
+
+func intResult(val uint64, unsigned bool) interface{} {
+	if unsigned {
+		return val
+	}
+	return int64(val)
+}
+
+// Compare two integers with respect to their signedness.
+func compareInts(a, b interface{}) int {
+	x, y := utils.ToNumber[uint64](a), utils.ToNumber[uint64](b)
+	xneg := !isUnsigned(a) && int64(x) < 0
+	yneg := !isUnsigned(b) && int64(y) < 0
+
+	switch {
+	case xneg && !yneg:
+		return -1
+	case !xneg && yneg:
+		return 1
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func powInt(base, exp uint64) uint64 {
+	result := uint64(1)
+	for exp > 0 {
+		if exp&1 != 0 {
+			result *= base
+		}
+		base *= base
+		exp >>= 1
+	}
+	return result
+}
+
+// Calculate binary operator op for operands a and b.
+//
+// Floats are used only if one of operands is float, otherwise the operation is performed
+// on 64-bit integers, and the results that don't fit are promoted to arbitrary-precision integers.
+func calcBinary(op operatorType, a, b interface{}) (interface{}, error) {
+	if isPattern(a) || isPattern(b) {
+		return calcPattern(op, a, b)
+	}
+	if isBytes(a) || isBytes(b) {
+		return calcBytes(op, a, b)
+	}
+	if isFlags(a) || isFlags(b) {
+		return calcFlags(op, a, b)
+	}
+	if isComplex(a) || isComplex(b) {
+		return calcComplex(op, a, b)
+	}
+	if isFloat(a) || isFloat(b) {
+		return calcFloat(op, a, b)
+	}
+	if useBigRat(op, a, b) {
+		return calcBigRat(op, a, b)
+	}
+	if isFixedInt(a) || isFixedInt(b) && op != OP_LEFTSHIFT && op != OP_RIGHTSHIFT {
+		return calcFixedInt(op, a, b)
+	}
+	if useBigInt(a, b) {
+		return calcBigInt(op, utils.ToBigInt(a), utils.ToBigInt(b), !isSigned(a) && !isSigned(b))
+	}
+	return calcInteger(op, a, b)
+}
+
+// Common type of the fixed-width integer operands.
+//
+// The wider type wins, if the widths are equal the unsigned type wins.
+// Untyped operands are converted to the type of the other operand.
+func fixedIntType(a, b interface{}) (width uint8, signed bool) {
+	ta, aok := a.(utils.FixedInt)
+	tb, bok := b.(utils.FixedInt)
+
+	switch {
+	case aok && bok:
+		if ta.Width == tb.Width {
+			return ta.Width, ta.Signed && tb.Signed
+		} else if ta.Width > tb.Width {
+			return ta.Width, ta.Signed
+		}
+		return tb.Width, tb.Signed
+	case aok:
+		return ta.Width, ta.Signed
+	default:
+		return tb.Width, tb.Signed
+	}
+}
+
+func calcFixedInt(op operatorType, a, b interface{}) (interface{}, error) {
+	switch op {
+	case OP_EQUALITY, OP_NOTEQ, OP_MORE, OP_LESS, OP_MOREEQ, OP_LESSEQ:
+		// compare mathematical values regardless of the types
+		return calcBigInt(op, utils.ToBigInt(a), utils.ToBigInt(b), false)
+	case OP_LEFTSHIFT, OP_RIGHTSHIFT:
+		x := a.(utils.FixedInt)
+		if isSigned(b) && utils.ToNumber[int64](b) < 0 {
+			return nil, fmt.Errorf("negative shift count %d", utils.ToNumber[int64](b))
+		}
+		n := utils.ToNumber[uint64](b)
+		if op == OP_LEFTSHIFT {
+			return utils.NewFixedInt(x.Bits<<n, x.Width, x.Signed), nil
+		}
+		if x.Signed {
+			return utils.NewFixedInt(uint64(x.Int64()>>n), x.Width, x.Signed), nil
+		}
+		return utils.NewFixedInt(x.Bits>>n, x.Width, x.Signed), nil
+	}
+
+	width, signed := fixedIntType(a, b)
+	x := utils.ToFixedInt(a, width, signed)
+	y := utils.ToFixedInt(b, width, signed)
+
+	var r uint64
+
+	switch op {
+	case OP_PLUS:
+		r = x.Bits + y.Bits
+	case OP_MINUS:
+		r = x.Bits - y.Bits
+	case OP_MULTIPLY:
+		r = x.Bits * y.Bits
+	case OP_DIVIDE, OP_INTDIVIDE, OP_MODULO:
+		if y.Bits == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		switch {
+		case op == OP_MODULO && signed:
+			r = uint64(x.Int64() % y.Int64())
+		case op == OP_MODULO:
+			r = x.Bits % y.Bits
+		case signed:
+			r = uint64(x.Int64() / y.Int64())
+		default:
+			r = x.Bits / y.Bits
+		}
+	case OP_POWER:
+		if isSigned(b) && utils.ToNumber[int64](b) < 0 {
+			return math.Pow(utils.ToNumber[float64](a), utils.ToNumber[float64](b)), nil
+		}
+		r = powInt(x.Bits, utils.ToNumber[uint64](b))
+	case OP_BITOR:
+		r = x.Bits | y.Bits
+	case OP_BITAND:
+		r = x.Bits & y.Bits
+	case OP_BITXOR:
+		r = x.Bits ^ y.Bits
+	case OP_BITCLEAR:
+		r = x.Bits &^ y.Bits
+	default:
+		return nil, fmt.Errorf("unable to find suitable action for operator type 0x%X", op)
+	}
+
+	return utils.NewFixedInt(r, width, signed), nil
+}
+
+func calcFloat(op operatorType, a, b interface{}) (interface{}, error) {
+	switch op {
+	case OP_EQUALITY:
+		return utils.ToNumber[float64](a) == utils.ToNumber[float64](b), nil
+	case OP_NOTEQ:
+		return utils.ToNumber[float64](a) != utils.ToNumber[float64](b), nil
+	case OP_MORE:
+		return utils.ToNumber[float64](a) > utils.ToNumber[float64](b), nil
+	case OP_LESS:
+		return utils.ToNumber[float64](a) < utils.ToNumber[float64](b), nil
+	case OP_MOREEQ:
+		return utils.ToNumber[float64](a) >= utils.ToNumber[float64](b), nil
+	case OP_LESSEQ:
+		return utils.ToNumber[float64](a) <= utils.ToNumber[float64](b), nil
+	case OP_PLUS:
+		return utils.ToNumber[float64](a) + utils.ToNumber[float64](b), nil
+	case OP_MINUS:
+		return utils.ToNumber[float64](a) - utils.ToNumber[float64](b), nil
+	case OP_MULTIPLY:
+		return utils.ToNumber[float64](a) * utils.ToNumber[float64](b), nil
+	case OP_DIVIDE:
+		opB := utils.ToNumber[float64](b)
+		if opB == 0 {
+			return math.Inf(int(utils.ToNumber[float64](a))), nil
+		}
+		return utils.ToNumber[float64](a) / opB, nil
+	case OP_INTDIVIDE:
+		return math.Trunc(utils.ToNumber[float64](a) / utils.ToNumber[float64](b)), nil
+	case OP_MODULO:
+		opB := utils.ToNumber[float64](b)
+		if opB == 0 {
+			return math.Inf(1), nil
+		}
+		return math.Mod(utils.ToNumber[float64](a), opB), nil
+	case OP_POWER:
+		x, y := utils.ToNumber[float64](a), utils.ToNumber[float64](b)
+		if x < 0 && y != math.Trunc(y) {
+			return calcComplex(op, a, b)
+		}
+		return math.Pow(x, y), nil
+	case OP_LEFTSHIFT:
+		return utils.ToNumber[uint64](a) << utils.ToNumber[uint64](b), nil
+	case OP_RIGHTSHIFT:
+		return utils.ToNumber[uint64](a) >> utils.ToNumber[uint64](b), nil
+	case OP_BITOR:
+		return utils.ToNumber[uint64](a) | utils.ToNumber[uint64](b), nil
+	case OP_BITAND:
+		return utils.ToNumber[uint64](a) & utils.ToNumber[uint64](b), nil
+	case OP_BITXOR:
+		return utils.ToNumber[uint64](a) ^ utils.ToNumber[uint64](b), nil
+	case OP_BITCLEAR:
+		return utils.ToNumber[uint64](a) &^ utils.ToNumber[uint64](b), nil
+	}
+
+	return nil, fmt.Errorf("unable to find suitable action for operator type 0x%X", op)
+}
+
+func calcInteger(op operatorType, a, b interface{}) (interface{}, error) {
+	x, y := utils.ToNumber[uint64](a), utils.ToNumber[uint64](b)
+	unsigned := intResultUnsigned(a, b)
+
+	switch op {
+	case OP_EQUALITY:
+		return compareInts(a, b) == 0, nil
+	case OP_NOTEQ:
+		return compareInts(a, b) != 0, nil
+	case OP_MORE:
+		return compareInts(a, b) > 0, nil
+	case OP_LESS:
+		return compareInts(a, b) < 0, nil
+	case OP_MOREEQ:
+		return compareInts(a, b) >= 0, nil
+	case OP_LESSEQ:
+		return compareInts(a, b) <= 0, nil
+	case OP_PLUS, OP_MINUS, OP_MULTIPLY:
+		var r uint64
+		switch op {
+		case OP_PLUS:
+			r = x + y
+		case OP_MINUS:
+			r = x - y
+		default:
+			r = x * y
+		}
+		return exactIntResult(op, a, b, r, unsigned)
+	case OP_DIVIDE:
+		if y == 0 {
+			return math.Inf(compareInts(a, uint64(0))), nil
+		}
+		if unsigned {
+			if x%y != 0 {
+				return float64(x) / float64(y), nil
+			}
+			return x / y, nil
+		}
+		if int64(x)%int64(y) != 0 {
+			return float64(int64(x)) / float64(int64(y)), nil
+		}
+		return int64(x) / int64(y), nil
+	case OP_INTDIVIDE, OP_MODULO:
+		if y == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		if op == OP_MODULO {
+			if unsigned {
+				return x % y, nil
+			}
+			return int64(x) % int64(y), nil
+		}
+		if unsigned {
+			return x / y, nil
+		}
+		return int64(x) / int64(y), nil
+	case OP_POWER:
+		if !isUnsigned(b) && int64(y) < 0 {
+			return math.Pow(utils.ToNumber[float64](a), utils.ToNumber[float64](b)), nil
+		}
+		return exactIntResult(op, a, b, powInt(x, y), isUnsigned(a))
+	case OP_LEFTSHIFT, OP_RIGHTSHIFT:
+		if !isUnsigned(b) && int64(y) < 0 {
+			return nil, fmt.Errorf("negative shift count %d", int64(y))
+		}
+		if op == OP_LEFTSHIFT {
+			return exactIntResult(op, a, b, x<<y, isUnsigned(a))
+		}
+		if isUnsigned(a) {
+			return x >> y, nil
+		}
+		return int64(x) >> y, nil
+	case OP_BITOR:
+		return intResult(x|y, unsigned), nil
+	case OP_BITAND:
+		return intResult(x&y, unsigned), nil
+	case OP_BITXOR:
+		return intResult(x^y, unsigned), nil
+	case OP_BITCLEAR:
+		return intResult(x&^y, unsigned), nil
+	}
+
+	return nil, fmt.Errorf("unable to find suitable action for operator type 0x%X", op)
+}
+
+// Calculate unary operator op for operand v.
+func calcUnary(op operatorType, v interface{}) (interface{}, error) {
+	if isPattern(v) {
+		return nil, fmt.Errorf("operator '%s' is not applicable to patterns", op.literal())
+	}
+	if isBytes(v) {
+		return nil, fmt.Errorf("operator '%s' is not applicable to byte arrays", op.literal())
+	}
+	if f, ok := v.(utils.Flags); ok && op == OP_BITINVERSE {
+		return utils.Flags{Bits: ^f.Bits, Set: f.Set}, nil
+	}
+	v = flagsBits(v)
+
+	if isComplex(v) {
+		return nil, fmt.Errorf("operator '%s' is not applicable to complex numbers", op.literal())
+	}
+
+	if x, ok := v.(*big.Int); ok {
+		switch op {
+		case OP_BITINVERSE:
+			return utils.NormalizeBigInt(new(big.Int).Not(x), false), nil
+		case OP_POPCNT:
+			return utils.BigOnesCount(x), nil
+		}
+	}
+
+	if x, ok := v.(utils.FixedInt); ok {
+		switch op {
+		case OP_BITINVERSE:
+			return utils.NewFixedInt(^x.Bits, x.Width, x.Signed), nil
+		case OP_POPCNT:
+			return uint64(bits.OnesCount64(x.Bits)), nil
+		}
+	}
+
+	switch op {
+	case OP_BITINVERSE:
+		if isFloat(v) || isUnsigned(v) {
+			return 0xFFFFFFFFFFFFFFFF ^ utils.ToNumber[uint64](v), nil
+		}
+		return ^utils.ToNumber[int64](v), nil
+	case OP_POPCNT:
+		return uint64(bits.OnesCount64(utils.ToNumber[uint64](v))), nil
+	}
+
+	return nil, fmt.Errorf("unable to find suitable action for operator type 0x%X", op)
+}
+
+func calcBigInt(op operatorType, a, b *big.Int, unsigned bool) (interface{}, error) {
+	r := new(big.Int)
+
+	switch op {
+	case OP_EQUALITY:
+		return a.Cmp(b) == 0, nil
+	case OP_NOTEQ:
+		return a.Cmp(b) != 0, nil
+	case OP_MORE:
+		return a.Cmp(b) > 0, nil
+	case OP_LESS:
+		return a.Cmp(b) < 0, nil
+	case OP_MOREEQ:
+		return a.Cmp(b) >= 0, nil
+	case OP_LESSEQ:
+		return a.Cmp(b) <= 0, nil
+	case OP_PLUS:
+		r.Add(a, b)
+	case OP_MINUS:
+		r.Sub(a, b)
+	case OP_MULTIPLY:
+		r.Mul(a, b)
+	case OP_DIVIDE:
+		if b.Sign() == 0 {
+			return math.Inf(a.Sign()), nil
+		}
+		m := new(big.Int)
+		r.QuoRem(a, b, m)
+		if m.Sign() != 0 {
+			f, _ := new(big.Float).Quo(new(big.Float).SetInt(a), new(big.Float).SetInt(b)).Float64()
+			return f, nil
+		}
+	case OP_INTDIVIDE:
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		r.Quo(a, b)
+	case OP_MODULO:
+		if b.Sign() == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		r.Rem(a, b)
+	case OP_POWER:
+		if b.Sign() < 0 {
+			return math.Pow(utils.ToNumber[float64](a), utils.ToNumber[float64](b)), nil
+		}
+		if a.BitLen() > 1 && (!b.IsInt64() || b.Int64() > maxBigIntBits || int64(a.BitLen()-1)*b.Int64() > maxBigIntBits) {
+			return nil, fmt.Errorf("result is too large")
+		}
+		r.Exp(a, b, nil)
+	case OP_LEFTSHIFT, OP_RIGHTSHIFT:
+		if b.Sign() < 0 {
+			return nil, fmt.Errorf("negative shift count %s", b)
+		}
+		if op == OP_RIGHTSHIFT {
+			if b.IsInt64() && b.Int64() < maxBigIntBits {
+				r.Rsh(a, uint(b.Int64()))
+			} else if a.Sign() < 0 {
+				r.SetInt64(-1)
+			}
+			break
+		}
+		if !b.IsInt64() || b.Int64() > maxBigIntBits || int64(a.BitLen())+b.Int64() > maxBigIntBits {
+			return nil, fmt.Errorf("result is too large")
+		}
+		r.Lsh(a, uint(b.Int64()))
+	case OP_BITOR:
+		r.Or(a, b)
+	case OP_BITAND:
+		r.And(a, b)
+	case OP_BITXOR:
+		r.Xor(a, b)
+	case OP_BITCLEAR:
+		r.AndNot(a, b)
+	default:
+		return nil, fmt.Errorf("unable to find suitable action for operator type 0x%X", op)
+	}
+
+	return utils.NormalizeBigInt(r, unsigned), nil
+}
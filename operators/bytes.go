@@ -0,0 +1,105 @@
+package operators
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+func isBytes(v interface{}) bool {
+	_, ok := v.([]byte)
+	return ok
+}
+
+// Calculate binary operator op for byte array operands.
+//
+// Byte arrays can be concatenated and compared for equality only.
+func calcBytes(op operatorType, a, b interface{}) (interface{}, error) {
+	x, isBytesA := a.([]byte)
+	y, isBytesB := b.([]byte)
+
+	switch {
+	case !isBytesA || !isBytesB:
+		break
+	case op == OP_PLUS:
+		r := make([]byte, 0, len(x)+len(y))
+		return append(append(r, x...), y...), nil
+	case op == OP_EQUALITY:
+		return bytes.Equal(x, y), nil
+	case op == OP_NOTEQ:
+		return !bytes.Equal(x, y), nil
+	}
+	return nil, fmt.Errorf("operator '%s' is not applicable to byte arrays", op.literal())
+}
+
+// Get the range of bytes from start to end exclusive or a single byte.
+func byteSliceBounds(data []byte, bounds interface{}) (start, end int64, single bool, err error) {
+	switch b := bounds.(type) {
+	case []interface{}:
+		if len(b) != 2 {
+			return 0, 0, false, fmt.Errorf("the byte slice must have two bounds")
+		}
+		start, end = utils.ToNumber[int64](b[0]), utils.ToNumber[int64](b[1])
+	default:
+		start = utils.ToNumber[int64](b)
+		end = start + 1
+		single = true
+	}
+
+	if start < 0 || end < start || end > int64(len(data)) {
+		if single {
+			return 0, 0, false, fmt.Errorf("the byte index %d is out of range of %d bytes", start, len(data))
+		}
+		return 0, 0, false, fmt.Errorf("the byte slice [%d:%d] is out of range of %d bytes", start, end, len(data))
+	}
+	return start, end, single, nil
+}
+
+// Get the bit slice of v or the bytes of the byte array v.
+func sliceValue(v, bounds interface{}) (interface{}, error) {
+	if data, ok := v.([]byte); ok {
+		start, end, single, err := byteSliceBounds(data, bounds)
+		if err != nil {
+			return nil, err
+		}
+		if single {
+			return uint64(data[start]), nil
+		}
+		return append([]byte{}, data[start:end]...), nil
+	}
+
+	hi, lo, err := sliceBounds(bounds)
+	if err != nil {
+		return nil, err
+	}
+	return getSlice(v, hi, lo), nil
+}
+
+// Replace the bit slice of v or the bytes of the byte array v with field.
+func assignSlice(v, bounds, field interface{}) (interface{}, error) {
+	if data, ok := v.([]byte); ok {
+		start, end, _, err := byteSliceBounds(data, bounds)
+		if err != nil {
+			return nil, err
+		}
+		replacement, ok := field.([]byte)
+		if !ok {
+			b := utils.ToNumber[int64](field)
+			if b < -128 || b > 255 {
+				return nil, fmt.Errorf("the value %v doesn't fit in a byte", field)
+			}
+			replacement = []byte{byte(b)}
+		}
+		r := make([]byte, 0, len(data)-int(end-start)+len(replacement))
+		r = append(r, data[:start]...)
+		r = append(r, replacement...)
+		return append(r, data[end:]...), nil
+	}
+
+	hi, lo, err := sliceBounds(bounds)
+	if err != nil {
+		return nil, err
+	}
+	return setSlice(v, hi, lo, field)
+}
@@ -0,0 +1,37 @@
+package operators
+
+import (
+	"fmt"
+	"math/cmplx"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+func isComplex(v interface{}) bool {
+	return utils.IsComplex(v)
+}
+
+func calcComplex(op operatorType, a, b interface{}) (interface{}, error) {
+	x, y := utils.ToComplex(a), utils.ToComplex(b)
+
+	switch op {
+	case OP_EQUALITY:
+		return x == y, nil
+	case OP_NOTEQ:
+		return x != y, nil
+	case OP_MORE, OP_LESS, OP_MOREEQ, OP_LESSEQ:
+		return nil, fmt.Errorf("complex numbers can't be ordered")
+	case OP_PLUS:
+		return x + y, nil
+	case OP_MINUS:
+		return x - y, nil
+	case OP_MULTIPLY:
+		return x * y, nil
+	case OP_DIVIDE:
+		return x / y, nil
+	case OP_POWER:
+		return cmplx.Pow(x, y), nil
+	}
+
+	return nil, fmt.Errorf("operator '%s' is not applicable to complex numbers", op.literal())
+}
@@ -0,0 +1,39 @@
+package operators
+
+import "github.com/dece2183/hexowl/utils"
+
+func isFlags(v interface{}) bool {
+	_, ok := v.(utils.Flags)
+	return ok
+}
+
+// Untag the flag set value.
+func flagsBits(v interface{}) interface{} {
+	if f, ok := v.(utils.Flags); ok {
+		return f.Bits
+	}
+	return v
+}
+
+// Calculate binary operator op for flag set operands.
+//
+// Bitwise operators keep the flag set of the left tagged operand, other operators work with plain bits.
+func calcFlags(op operatorType, a, b interface{}) (interface{}, error) {
+	set, ok := a.(utils.Flags)
+	if !ok {
+		set = b.(utils.Flags)
+	}
+
+	r, err := calcBinary(op, flagsBits(a), flagsBits(b))
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case OP_BITOR, OP_BITAND, OP_BITXOR, OP_BITCLEAR:
+		if utils.IsInteger(r) {
+			return utils.Flags{Bits: utils.ToNumber[uint64](r), Set: set.Set}, nil
+		}
+	}
+	return r, nil
+}
@@ -1,5 +1,7 @@
 package operators
 
+import "fmt"
+
 // Operator types
 const (
 	OP_NONE operatorType = iota
@@ -28,12 +30,13 @@ const (
 	OP_MOREEQ operatorType = iota
 	OP_LESSEQ operatorType = iota
 
-	OP_PLUS     operatorType = iota
-	OP_MINUS    operatorType = iota
-	OP_MULTIPLY operatorType = iota
-	OP_DIVIDE   operatorType = iota
-	OP_MODULO   operatorType = iota
-	OP_POWER    operatorType = iota
+	OP_PLUS      operatorType = iota
+	OP_MINUS     operatorType = iota
+	OP_MULTIPLY  operatorType = iota
+	OP_DIVIDE    operatorType = iota
+	OP_INTDIVIDE operatorType = iota
+	OP_MODULO    operatorType = iota
+	OP_POWER     operatorType = iota
 
 	OP_BITOR      operatorType = iota
 	OP_BITAND     operatorType = iota
@@ -46,6 +49,8 @@ const (
 	OP_LOGICNOT operatorType = iota
 	OP_POPCNT   operatorType = iota
 
+	OP_SLICE operatorType = iota
+
 	OP_LOCALVAR    operatorType = iota
 	OP_USERVAR     operatorType = iota
 	OP_CONSTANT    operatorType = iota
@@ -84,6 +89,7 @@ var opStringRepresent = map[string]operatorType{
 	"-":  OP_MINUS,
 	"*":  OP_MULTIPLY,
 	"/":  OP_DIVIDE,
+	"//": OP_INTDIVIDE,
 	"%":  OP_MODULO,
 	"**": OP_POWER,
 
@@ -107,3 +113,13 @@ func (op operatorType) IsUnary() bool {
 func (op operatorType) IsAssign() bool {
 	return op >= OP_ASSIGN && op <= OP_ASSIGNBITOR
 }
+
+// The literal representation of the operator.
+func (op operatorType) literal() string {
+	for lit, t := range opStringRepresent {
+		if t == op {
+			return lit
+		}
+	}
+	return fmt.Sprintf("0x%X", uint(op))
+}
@@ -1,8 +1,10 @@
 package operators
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -112,6 +114,32 @@ func execUserFunc(f user.Func, args []interface{}) (result interface{}, err erro
 	return
 }
 
+// Parse integer literal in base.
+//
+// Decimal literals are parsed as int64, other ones as uint64.
+// Literals that don't fit into 64 bits are parsed as *big.Int.
+func parseInteger(literal string, base int) (interface{}, error) {
+	var val interface{}
+	var err error
+
+	literal = strings.ReplaceAll(literal, "_", "")
+	if base == 10 {
+		val, err = strconv.ParseInt(literal, base, 64)
+	} else {
+		val, err = strconv.ParseUint(literal, base, 64)
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		bigVal, ok := new(big.Int).SetString(literal, base)
+		if !ok {
+			return nil, err
+		}
+		return bigVal, nil
+	}
+
+	return val, err
+}
+
 func getType(op string) operatorType {
 	t, ok := opStringRepresent[op]
 	if ok {
@@ -127,7 +155,7 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 	newOp := &Operator{}
 
 	if len(words) == 0 {
-		newOp.Result = uint64(0)
+		newOp.Result = int64(0)
 		return newOp, nil
 	} else if len(words) == 1 {
 		w := words[0]
@@ -144,6 +172,8 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 			} else if builtin.HasConstant(w.Literal) {
 				newOp.Type = OP_CONSTANT
 				newOp.Result = w.Literal
+			} else if set, value, found := user.FindFlag(w.Literal); found {
+				newOp.Result = utils.Flags{Bits: value, Set: set}
 			} else if user.HasFunction(w.Literal) {
 				newOp.Type = OP_USERFUNC
 				newOp.Result = w.Literal
@@ -186,7 +216,14 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 			}
 			return nil, fmt.Errorf("there is no function named '%s'", w.Literal)
 
-		case utils.W_NUM_DEC, utils.W_NUM_HEX, utils.W_NUM_BIN, utils.W_NUM_SCI:
+		case utils.W_NUM_DEC, utils.W_NUM_HEX, utils.W_NUM_BIN, utils.W_NUM_SCI, utils.W_NUM_OCT, utils.W_NUM_RADIX, utils.W_NUM_HEXF, utils.W_NUM_PAT:
+			// parse integer type suffix
+			var typeSuffix string
+			if pos := strings.IndexAny(w.Literal, "ui"); pos > -1 && w.Type != utils.W_NUM_SCI && w.Type != utils.W_NUM_RADIX && w.Type != utils.W_NUM_HEXF && w.Type != utils.W_NUM_PAT {
+				typeSuffix = w.Literal[pos:]
+				w.Literal = w.Literal[:pos]
+			}
+
 			// parse number constant
 			switch w.Type {
 			case utils.W_NUM_SCI:
@@ -202,24 +239,90 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 				}
 				newOp.Result = mantisse * math.Pow(10, order)
 			case utils.W_NUM_DEC:
-				newOp.Result, err = strconv.ParseFloat(strings.ReplaceAll(w.Literal, "_", ""), 64)
+				if strings.Contains(w.Literal, ".") {
+					newOp.Result, err = strconv.ParseFloat(strings.ReplaceAll(w.Literal, "_", ""), 64)
+				} else {
+					newOp.Result, err = parseInteger(w.Literal, 10)
+				}
 				if err != nil {
 					return nil, fmt.Errorf("unable to parse literal '%s' as number", w.Literal)
 				}
 			case utils.W_NUM_HEX:
-				newOp.Result, err = strconv.ParseUint(strings.ReplaceAll(w.Literal, "_", ""), 16, 64)
+				newOp.Result, err = parseInteger(w.Literal, 16)
 				if err != nil {
 					return nil, fmt.Errorf("unable to parse literal '%s' as hex number", w.Literal)
 				}
 			case utils.W_NUM_BIN:
-				newOp.Result, err = strconv.ParseUint(strings.ReplaceAll(w.Literal, "_", ""), 2, 64)
+				newOp.Result, err = parseInteger(w.Literal, 2)
 				if err != nil {
 					return nil, fmt.Errorf("unable to parse literal '%s' as bin number", w.Literal)
 				}
+			case utils.W_NUM_OCT:
+				newOp.Result, err = parseInteger(w.Literal, 8)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse literal '%s' as oct number", w.Literal)
+				}
+			case utils.W_NUM_RADIX:
+				num := strings.SplitN(w.Literal, "#", 2)
+				var radix uint64
+				radix, err = strconv.ParseUint(num[0], 10, 8)
+				if err != nil || radix < 2 || radix > 36 {
+					return nil, fmt.Errorf("the radix of literal '%s' must be in range [2,36]", w.Literal)
+				}
+				newOp.Result, err = parseInteger(num[1], int(radix))
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse literal '%s' as base %d number", w.Literal, radix)
+				}
+			case utils.W_NUM_HEXF:
+				literal := "0x" + w.Literal
+				if !strings.ContainsAny(literal, "pP") {
+					literal += "p0"
+				}
+				newOp.Result, err = strconv.ParseFloat(literal, 64)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse literal '%s' as hex float number", w.Literal)
+				}
+			case utils.W_NUM_PAT:
+				newOp.Result, err = utils.ParsePattern(w.Literal)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if typeSuffix == "i" {
+				// imaginary literal
+				newOp.Result = complex(0, utils.ToNumber[float64](newOp.Result))
+			} else if len(typeSuffix) > 0 {
+				width, signed, found := utils.FixedIntType(typeSuffix)
+				if !found {
+					return nil, fmt.Errorf("unknown integer type suffix '%s'", typeSuffix)
+				}
+				if !utils.IsInteger(newOp.Result) {
+					return nil, fmt.Errorf("unable to apply type suffix '%s' to non-integer literal '%s'", typeSuffix, w.Literal)
+				}
+				newOp.Result = utils.ToFixedInt(newOp.Result, width, signed)
 			}
 
 		case utils.W_STR:
+			newOp.Result, err = utils.Unescape(w.Literal)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse string \"%s\": %s", w.Literal, err)
+			}
+
+		case utils.W_RAWSTR:
 			newOp.Result = w.Literal
+
+		case utils.W_CHAR:
+			newOp.Result, err = utils.ParseChar(w.Literal)
+			if err != nil {
+				return nil, err
+			}
+
+		case utils.W_BYTES:
+			newOp.Result, err = utils.ParseBytes(w.Literal)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		return newOp, nil
@@ -231,7 +334,7 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 
 	bracketsCount := 0
 
-	if words[0].Type == utils.W_CTL && words[len(words)-1].Type == utils.W_CTL {
+	if words[0].Type == utils.W_CTL && words[len(words)-1].Type == utils.W_CTL && words[len(words)-1].Literal != "]" {
 		if words[0].Literal != "(" {
 			return nil, fmt.Errorf("missing opening bracket")
 		}
@@ -243,7 +346,7 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 			if words[i].Type != utils.W_CTL {
 				continue
 			}
-			if words[i].Literal == "(" {
+			if isOpeningBracket(words[i]) {
 				bracketsCount++
 			} else {
 				bracketsCount--
@@ -264,7 +367,7 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 		w := words[i]
 
 		if w.Type == utils.W_CTL {
-			if w.Literal == "(" {
+			if isOpeningBracket(w) {
 				bracketsCount++
 			} else {
 				bracketsCount--
@@ -301,7 +404,10 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 	}
 
 	if minPriorityWord == nil {
-		if len(words) > 0 && words[0].Type == utils.W_FUNC {
+		if len(words) > 1 && words[len(words)-1].Literal == "]" {
+			// Bit slice
+			return generateSlice(words, localVars)
+		} else if len(words) > 0 && words[0].Type == utils.W_FUNC {
 			if len(words) < 3 {
 				return nil, fmt.Errorf("missing function '%s' arguments", words[0].Literal)
 			}
@@ -383,6 +489,20 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 			}
 			lit := words[minPriorityIndex-1].Literal
 
+			// Assignment to bit slice of a variable
+			var sliceIndex *Operator
+			if lit == "]" {
+				bracketIndex := findOpeningBracket(words[:minPriorityIndex])
+				if bracketIndex < 1 {
+					return nil, fmt.Errorf("missing a variable on left side of operator '%s'", minPriorityWord.Literal)
+				}
+				sliceIndex, err = generateSliceIndex(words[bracketIndex+1:minPriorityIndex-1], localVars)
+				if err != nil {
+					return nil, err
+				}
+				lit = words[bracketIndex-1].Literal
+			}
+
 			_, foundLocal := getLocalVariable(localVars, lit)
 			foundUser := user.HasVariable(lit)
 			if foundLocal || newOp.Type == OP_LOCALASSIGN {
@@ -404,6 +524,14 @@ func Generate(words []utils.Word, localVars map[string]interface{}) (*Operator,
 			} else {
 				return nil, fmt.Errorf("there is no user variable named '%s'", lit)
 			}
+
+			if sliceIndex != nil {
+				newOp.OperandA = &Operator{
+					Type:     OP_SLICE,
+					OperandA: newOp.OperandA,
+					OperandB: sliceIndex,
+				}
+			}
 		} else {
 			newOp.OperandA, err = Generate(words[:minPriorityIndex], localVars)
 			if err != nil {
@@ -466,6 +594,12 @@ func Calculate(op *Operator, localVars map[string]interface{}) (interface{}, err
 			if err != nil {
 				return nil, err
 			}
+		} else if op.OperandA != nil && op.OperandA.Type == OP_SLICE {
+			// calculate only the bounds of the assigned bit slice
+			op.OperandA.OperandB.Result, err = Calculate(op.OperandA.OperandB, localVars)
+			if err != nil {
+				return nil, err
+			}
 		}
 		if op.OperandB != nil {
 			op.OperandB.Result, err = Calculate(op.OperandB, localVars)
@@ -477,3 +611,12 @@ func Calculate(op *Operator, localVars map[string]interface{}) (interface{}, err
 
 	return opDoAction(op, localVars)
 }
+
+// Parse and calculate expression string.
+func Evaluate(expr string, localVars map[string]interface{}) (interface{}, error) {
+	op, err := Generate(utils.ParsePrompt(expr), localVars)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(op, localVars)
+}
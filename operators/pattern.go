@@ -0,0 +1,38 @@
+package operators
+
+import (
+	"fmt"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+func isPattern(v interface{}) bool {
+	_, ok := v.(utils.Pattern)
+	return ok
+}
+
+// Calculate binary operator op for binary pattern operands.
+//
+// Only equality operators are applicable, the value is equal to the pattern if it matches it.
+func calcPattern(op operatorType, a, b interface{}) (interface{}, error) {
+	if op != OP_EQUALITY && op != OP_NOTEQ {
+		return nil, fmt.Errorf("operator '%s' is not applicable to patterns", op.literal())
+	}
+
+	var equal bool
+	pa, isPatternA := a.(utils.Pattern)
+	pb, isPatternB := b.(utils.Pattern)
+	switch {
+	case isPatternA && isPatternB:
+		equal = pa == pb
+	case isPatternA:
+		equal = pa.Match(b)
+	default:
+		equal = pb.Match(a)
+	}
+
+	if op == OP_NOTEQ {
+		return !equal, nil
+	}
+	return equal, nil
+}
@@ -0,0 +1,102 @@
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+func isBigRat(v interface{}) bool {
+	_, ok := v.(*big.Rat)
+	return ok
+}
+
+func useBigRat(op operatorType, a, b interface{}) bool {
+	if isBigRat(a) || isBigRat(b) {
+		return true
+	}
+	if !utils.ModeEnabled(utils.M_RATIONAL) || isFixedInt(a) || isFixedInt(b) {
+		return false
+	}
+	switch op {
+	case OP_DIVIDE:
+		return true
+	case OP_POWER:
+		return isSigned(b) && utils.ToNumber[int64](b) < 0
+	}
+	return false
+}
+
+func calcBigRat(op operatorType, a, b interface{}) (interface{}, error) {
+	for _, v := range []interface{}{a, b} {
+		if !utils.IsFinite(v) {
+			return nil, fmt.Errorf("unable to convert %v to a rational number", v)
+		}
+	}
+	x, y := utils.ToBigRat(a), utils.ToBigRat(b)
+	r := new(big.Rat)
+
+	switch op {
+	case OP_EQUALITY:
+		return x.Cmp(y) == 0, nil
+	case OP_NOTEQ:
+		return x.Cmp(y) != 0, nil
+	case OP_MORE:
+		return x.Cmp(y) > 0, nil
+	case OP_LESS:
+		return x.Cmp(y) < 0, nil
+	case OP_MOREEQ:
+		return x.Cmp(y) >= 0, nil
+	case OP_LESSEQ:
+		return x.Cmp(y) <= 0, nil
+	case OP_PLUS:
+		r.Add(x, y)
+	case OP_MINUS:
+		r.Sub(x, y)
+	case OP_MULTIPLY:
+		r.Mul(x, y)
+	case OP_DIVIDE:
+		if y.Sign() == 0 {
+			return math.Inf(x.Sign()), nil
+		}
+		r.Quo(x, y)
+	case OP_INTDIVIDE:
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		return utils.NormalizeBigInt(utils.ToBigInt(r.Quo(x, y)), false), nil
+	case OP_MODULO:
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("integer division by zero")
+		}
+		q := new(big.Rat).SetInt(utils.ToBigInt(new(big.Rat).Quo(x, y)))
+		r.Sub(x, q.Mul(q, y))
+	case OP_POWER:
+		if !y.IsInt() {
+			return math.Pow(utils.ToNumber[float64](x), utils.ToNumber[float64](y)), nil
+		}
+		exp := y.Num()
+		if x.Sign() == 0 && exp.Sign() < 0 {
+			return math.Inf(1), nil
+		}
+		num, err := calcBigInt(OP_POWER, x.Num(), new(big.Int).Abs(exp), false)
+		if err != nil {
+			return nil, err
+		}
+		den, err := calcBigInt(OP_POWER, x.Denom(), new(big.Int).Abs(exp), false)
+		if err != nil {
+			return nil, err
+		}
+		r.SetFrac(utils.ToBigInt(num), utils.ToBigInt(den))
+		if exp.Sign() < 0 {
+			r.Inv(r)
+		}
+	default:
+		// bitwise operators are applied to the integer part
+		return calcBinary(op, utils.NormalizeBigInt(utils.ToBigInt(x), false), utils.NormalizeBigInt(utils.ToBigInt(y), false))
+	}
+
+	return utils.NormalizeBigRat(r), nil
+}
@@ -0,0 +1,158 @@
+package operators
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+func isOpeningBracket(w utils.Word) bool {
+	return w.Literal == "(" || w.Literal == "["
+}
+
+// Find the index of the bracket that opens the last word of words.
+func findOpeningBracket(words []utils.Word) int {
+	bracketsCount := 0
+	for i := len(words) - 1; i >= 0; i-- {
+		if words[i].Type != utils.W_CTL {
+			continue
+		}
+		if isOpeningBracket(words[i]) {
+			bracketsCount--
+		} else {
+			bracketsCount++
+		}
+		if bracketsCount == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Generate bit slice operator from words like x[7:4].
+func generateSlice(words []utils.Word, localVars map[string]interface{}) (*Operator, error) {
+	var err error
+
+	bracketIndex := findOpeningBracket(words)
+	if bracketIndex < 0 || words[bracketIndex].Literal != "[" {
+		return nil, fmt.Errorf("missing opening bracket")
+	}
+	if bracketIndex == 0 {
+		// square brackets without a value are just grouping, like [1, 2, 3]
+		return Generate(words[1:len(words)-1], localVars)
+	}
+
+	newOp := &Operator{Type: OP_SLICE}
+	newOp.OperandA, err = Generate(words[:bracketIndex], localVars)
+	if err != nil {
+		return nil, err
+	}
+	newOp.OperandB, err = generateSliceIndex(words[bracketIndex+1:len(words)-1], localVars)
+	if err != nil {
+		return nil, err
+	}
+	return newOp, nil
+}
+
+// Generate bit slice bounds operator from words like 7:4 or single bit index.
+func generateSliceIndex(words []utils.Word, localVars map[string]interface{}) (*Operator, error) {
+	var err error
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("missing bit slice index")
+	}
+
+	bracketsCount := 0
+	for i, w := range words {
+		if w.Type == utils.W_CTL {
+			if isOpeningBracket(w) {
+				bracketsCount++
+			} else {
+				bracketsCount--
+			}
+			continue
+		}
+		if w.Type != utils.W_OP || w.Literal != ":" || bracketsCount > 0 {
+			continue
+		}
+
+		newOp := &Operator{Type: OP_ENUMERATE}
+		newOp.OperandA, err = Generate(words[:i], localVars)
+		if err != nil {
+			return nil, err
+		}
+		newOp.OperandB, err = Generate(words[i+1:], localVars)
+		if err != nil {
+			return nil, err
+		}
+		return newOp, nil
+	}
+
+	return Generate(words, localVars)
+}
+
+// Get high and low bit of the slice from calculated bounds.
+func sliceBounds(bounds interface{}) (hi, lo int64, err error) {
+	switch b := bounds.(type) {
+	case []interface{}:
+		if len(b) != 2 {
+			return 0, 0, fmt.Errorf("the bit slice must have two bounds")
+		}
+		hi, lo = utils.ToNumber[int64](b[0]), utils.ToNumber[int64](b[1])
+	default:
+		hi = utils.ToNumber[int64](b)
+		lo = hi
+	}
+
+	if lo < 0 {
+		return 0, 0, fmt.Errorf("negative bit index %d", lo)
+	}
+	if hi < lo {
+		return 0, 0, fmt.Errorf("the high bit %d of slice is less than the low bit %d", hi, lo)
+	}
+	if hi >= maxBigIntBits {
+		return 0, 0, fmt.Errorf("bit index %d is too large", hi)
+	}
+	return hi, lo, nil
+}
+
+func sliceMask(hi, lo int64) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(hi-lo+1))
+	return mask.Sub(mask, big.NewInt(1))
+}
+
+// Get bits from hi to lo of v.
+func getSlice(v interface{}, hi, lo int64) interface{} {
+	r := new(big.Int).Rsh(utils.ToBigInt(v), uint(lo))
+	return utils.NormalizeBigInt(r.And(r, sliceMask(hi, lo)), true)
+}
+
+// Replace bits from hi to lo of v with field keeping the type of v.
+func setSlice(v interface{}, hi, lo int64, field interface{}) (interface{}, error) {
+	if x, ok := v.(utils.FixedInt); ok && hi >= int64(x.Width) {
+		return nil, fmt.Errorf("bit index %d is out of range of %s", hi, x.TypeName())
+	}
+
+	mask := sliceMask(hi, lo)
+	f := new(big.Int).And(utils.ToBigInt(field), mask)
+	r := utils.ToBigInt(v)
+	r.AndNot(r, mask.Lsh(mask, uint(lo)))
+	r.Or(r, f.Lsh(f, uint(lo)))
+
+	switch x := v.(type) {
+	case utils.FixedInt:
+		return utils.NewFixedInt(utils.TruncBigInt(r), x.Width, x.Signed), nil
+	case *big.Int:
+		return utils.NormalizeBigInt(r, false), nil
+	case int64:
+		if hi < 64 {
+			return int64(utils.TruncBigInt(r)), nil
+		}
+	case uint64:
+		if hi < 64 {
+			return utils.TruncBigInt(r), nil
+		}
+	}
+	return utils.NormalizeBigInt(r, !isSigned(v)), nil
+}
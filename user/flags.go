@@ -0,0 +1,106 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dece2183/hexowl/utils"
+)
+
+// Named flag of the flag set.
+type Flag struct {
+	Name  string
+	Value uint64
+}
+
+// Flag set, a list of named bit flags.
+type FlagSet struct {
+	Flags []Flag
+}
+
+var flagSets = map[string]FlagSet{}
+
+// Is flag set presented in the user flag sets map.
+func HasFlagSet(name string) bool {
+	_, found := flagSets[name]
+	return found
+}
+
+// Get user flag set by name from the user flag sets map.
+func GetFlagSet(name string) (set FlagSet, found bool) {
+	set, found = flagSets[name]
+	return
+}
+
+// Set user flag set with given name.
+func SetFlagSet(name string, set FlagSet) {
+	flagSets[name] = set
+}
+
+// Delete user flag set with name.
+func DeleteFlagSet(name string) {
+	delete(flagSets, name)
+}
+
+// Return the user flag sets map.
+func ListFlagSets() map[string]FlagSet {
+	return flagSets
+}
+
+// Delete all user flag sets.
+func DropFlagSets() {
+	for name := range flagSets {
+		delete(flagSets, name)
+	}
+}
+
+// Find flag by name in all user flag sets.
+//
+// If several sets have the flag the first set in alphabetical order is used.
+func FindFlag(name string) (setName string, value uint64, found bool) {
+	setNames := make([]string, 0, len(flagSets))
+	for key := range flagSets {
+		setNames = append(setNames, key)
+	}
+	sort.Strings(setNames)
+
+	for _, setName = range setNames {
+		for _, f := range flagSets[setName].Flags {
+			if f.Name == name {
+				return setName, f.Value, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// Format bits symbolically like TX|ERR.
+//
+// The bits that don't match any flag are added as a hex remainder.
+func (s FlagSet) Format(bits uint64) string {
+	names := make([]string, 0)
+	rest := bits
+	for _, f := range s.Flags {
+		if f.Value != 0 && bits&f.Value == f.Value {
+			names = append(names, f.Name)
+			rest &^= f.Value
+		}
+	}
+
+	if rest != 0 || len(names) == 0 {
+		names = append(names, fmt.Sprintf("0x%X", rest))
+	}
+	return strings.Join(names, "|")
+}
+
+// Format the tagged value symbolically with its flag set.
+//
+// If the flag set doesn't exist anymore the value is formatted in hex.
+func FormatFlags(v utils.Flags) string {
+	set, found := flagSets[v.Set]
+	if !found {
+		return v.String()
+	}
+	return set.Format(v.Bits)
+}
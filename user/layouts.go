@@ -0,0 +1,84 @@
+package user
+
+import "fmt"
+
+// Named bit field of the register layout.
+type LayoutField struct {
+	Name string
+	Hi   uint8
+	Lo   uint8
+}
+
+// Register layout, a list of named bit fields.
+type Layout struct {
+	Fields []LayoutField
+}
+
+var layouts = map[string]Layout{}
+
+// Is layout presented in the user layouts map.
+func HasLayout(name string) bool {
+	_, found := layouts[name]
+	return found
+}
+
+// Get user layout by name from the user layouts map.
+func GetLayout(name string) (layout Layout, found bool) {
+	layout, found = layouts[name]
+	return
+}
+
+// Set user layout with given name.
+func SetLayout(name string, layout Layout) {
+	layouts[name] = layout
+}
+
+// Delete user layout with name.
+func DeleteLayout(name string) {
+	delete(layouts, name)
+}
+
+// Return the user layouts map.
+func ListLayouts() map[string]Layout {
+	return layouts
+}
+
+// Delete all user layouts.
+func DropLayouts() {
+	for name := range layouts {
+		delete(layouts, name)
+	}
+}
+
+// Find field of the layout by name.
+func (l Layout) Field(name string) (field LayoutField, found bool) {
+	for _, f := range l.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return LayoutField{}, false
+}
+
+// Width of the field in bits.
+func (f LayoutField) Width() uint8 {
+	return f.Hi - f.Lo + 1
+}
+
+// Mask of the field bits in the register.
+func (f LayoutField) Mask() uint64 {
+	if f.Width() >= 64 {
+		return 0xFFFFFFFFFFFFFFFF
+	}
+	return ((uint64(1) << f.Width()) - 1) << f.Lo
+}
+
+// fmt.Stringer interface implementation.
+//
+// Returns the field in the regdef form, like MODE:3:1.
+func (f LayoutField) String() string {
+	if f.Hi == f.Lo {
+		return fmt.Sprintf("%s:%d", f.Name, f.Lo)
+	}
+	return fmt.Sprintf("%s:%d:%d", f.Name, f.Hi, f.Lo)
+}
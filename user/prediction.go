@@ -19,5 +19,16 @@ func Predict(word string) string {
 		}
 	}
 
+	for _, set := range flagSets {
+		for _, f := range set.Flags {
+			if len(f.Name) < len(word) {
+				continue
+			}
+			if f.Name[:len(word)] == word {
+				return f.Name
+			}
+		}
+	}
+
 	return ""
 }
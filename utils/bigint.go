@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+var bigUint64Mask = new(big.Int).SetUint64(math.MaxUint64)
+
+// Is i an integer number (int64, uint64, *big.Int and etc.).
+func IsInteger(i interface{}) bool {
+	switch i.(type) {
+	case byte, int, uint, int64, uint64, *big.Int:
+		return true
+	}
+	return false
+}
+
+// Convert any number to *big.Int.
+//
+// Floats are truncated towards zero, NaN and infinities are converted to zero.
+func ToBigInt(i interface{}) *big.Int {
+	switch v := i.(type) {
+	case *big.Int:
+		return new(big.Int).Set(v)
+	case int:
+		return big.NewInt(int64(v))
+	case int64:
+		return big.NewInt(v)
+	case FixedInt:
+		if v.Signed {
+			return big.NewInt(v.Int64())
+		}
+		return new(big.Int).SetUint64(v.Bits)
+	case *big.Rat:
+		return new(big.Int).Quo(v.Num(), v.Denom())
+	case float32:
+		return ToBigInt(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return new(big.Int)
+		}
+		r, _ := big.NewFloat(v).Int(nil)
+		return r
+	}
+	return new(big.Int).SetUint64(ToNumber[uint64](i))
+}
+
+// Return the lowest 64 bits of x in two's complement representation.
+func TruncBigInt(x *big.Int) uint64 {
+	if x.Sign() >= 0 && x.IsUint64() {
+		return x.Uint64()
+	}
+	return new(big.Int).And(x, bigUint64Mask).Uint64()
+}
+
+// Convert x to the narrowest type that can hold it: int64, uint64 or *big.Int.
+//
+// If unsigned is true uint64 is preferred over int64 for non-negative values.
+func NormalizeBigInt(x *big.Int, unsigned bool) interface{} {
+	if unsigned && x.IsUint64() {
+		return x.Uint64()
+	}
+	if x.IsInt64() {
+		return x.Int64()
+	}
+	if x.IsUint64() {
+		return x.Uint64()
+	}
+	return x
+}
+
+// Return the number of one bits in the absolute value of x.
+func BigOnesCount(x *big.Int) uint64 {
+	var cnt int
+	for _, w := range x.Bits() {
+		cnt += bits.OnesCount(uint(w))
+	}
+	return uint64(cnt)
+}
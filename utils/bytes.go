@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Parse the hex digits of the byte array literal, spaces, underscores and colons are ignored.
+func ParseBytes(s string) ([]byte, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '_', ':':
+			return -1
+		}
+		return r
+	}, s)
+
+	if len(digits)%2 != 0 {
+		return nil, fmt.Errorf("the byte array \"%s\" must have an even number of hex digits", s)
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse byte array \"%s\"", s)
+	}
+	return b, nil
+}
+
+// Format the byte array in the literal form, like b"DE AD BE EF".
+func FormatBytes(b []byte) string {
+	var s strings.Builder
+	s.WriteString("b\"")
+	for i, v := range b {
+		if i > 0 {
+			s.WriteByte(' ')
+		}
+		fmt.Fprintf(&s, "%02X", v)
+	}
+	s.WriteByte('"')
+	return s.String()
+}
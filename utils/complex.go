@@ -0,0 +1,21 @@
+package utils
+
+// Is i a complex number.
+func IsComplex(i interface{}) bool {
+	switch i.(type) {
+	case complex64, complex128:
+		return true
+	}
+	return false
+}
+
+// Convert any number to complex128, real numbers get zero imaginary part.
+func ToComplex(i interface{}) complex128 {
+	switch v := i.(type) {
+	case complex128:
+		return v
+	case complex64:
+		return complex128(v)
+	}
+	return complex(ToNumber[float64](i), 0)
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// Byte order of the host machine.
+var HostByteOrder = hostByteOrder()
+
+func hostByteOrder() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var simpleEscapes = map[byte]byte{
+	'a':  '\a',
+	'b':  '\b',
+	'e':  0x1B,
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+	'v':  '\v',
+	'\\': '\\',
+	'\'': '\'',
+	'"':  '"',
+	'?':  '?',
+}
+
+// Replace the C-style escape sequences of the string or character literal.
+//
+// Supported sequences are \a \b \e \f \n \r \t \v \\ \' \" \?, octal \NNN,
+// hex byte \xNN and Unicode code points \uNNNN and \UNNNNNNNN.
+// The backslash of unknown sequences is kept in the string.
+func Unescape(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			out.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("unterminated escape sequence at position %d", i)
+		}
+
+		c := s[i+1]
+		if b, ok := simpleEscapes[c]; ok {
+			out.WriteByte(b)
+			i++
+			continue
+		}
+
+		var digits, base int
+		switch {
+		case c >= '0' && c <= '7':
+			// up to three octal digits
+			digits, base = 1, 8
+			for digits < 3 && i+1+digits < len(s) && s[i+1+digits] >= '0' && s[i+1+digits] <= '7' {
+				digits++
+			}
+			v, _ := parseEscapeDigits(s[i+1:i+1+digits], base)
+			if v > 0xFF {
+				return "", fmt.Errorf("octal escape sequence at position %d is out of byte range", i)
+			}
+			out.WriteByte(byte(v))
+			i += digits
+			continue
+		case c == 'x':
+			digits, base = 2, 16
+		case c == 'u':
+			digits, base = 4, 16
+		case c == 'U':
+			digits, base = 8, 16
+		default:
+			// unknown sequences are kept as is like C compilers do
+			out.WriteByte('\\')
+			continue
+		}
+
+		if i+2+digits > len(s) {
+			return "", fmt.Errorf("escape sequence at position %d must have %d hex digits", i, digits)
+		}
+		v, ok := parseEscapeDigits(s[i+2:i+2+digits], base)
+		if !ok {
+			return "", fmt.Errorf("escape sequence at position %d must have %d hex digits", i, digits)
+		}
+		if c == 'x' {
+			out.WriteByte(byte(v))
+		} else {
+			if !utf8.ValidRune(rune(v)) {
+				return "", fmt.Errorf("escape sequence at position %d is not a valid Unicode code point", i)
+			}
+			out.WriteRune(rune(v))
+		}
+		i += 1 + digits
+	}
+
+	return out.String(), nil
+}
+
+func parseEscapeDigits(s string, base int) (uint32, bool) {
+	var v uint32
+	for _, c := range []byte(s) {
+		var d uint32
+		switch {
+		case c >= '0' && c <= '9':
+			d = uint32(c - '0')
+		case c >= 'a' && c <= 'f':
+			d = uint32(c-'a') + 10
+		case c >= 'A' && c <= 'F':
+			d = uint32(c-'A') + 10
+		default:
+			return 0, false
+		}
+		if d >= uint32(base) {
+			return 0, false
+		}
+		v = v*uint32(base) + d
+	}
+	return v, true
+}
+
+// Parse the character literal without quotes, like A or \n.
+//
+// A single character gives its Unicode code point,
+// several characters are packed in big-endian order like the C multi-character constants.
+func ParseChar(s string) (int64, error) {
+	u, err := Unescape(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(u) == 0 {
+		return 0, fmt.Errorf("empty character literal")
+	}
+	if r, size := utf8.DecodeRuneInString(u); r != utf8.RuneError && size == len(u) {
+		return int64(r), nil
+	}
+	if len(u) > 8 {
+		return 0, fmt.Errorf("the character literal '%s' is longer than 8 bytes", s)
+	}
+
+	var v uint64
+	for _, b := range []byte(u) {
+		v = v<<8 | uint64(b)
+	}
+	return int64(v), nil
+}
+
+// Format the bytes as the character literal if all of them are printable ASCII characters.
+func FormatChars(b []byte) (string, bool) {
+	if len(b) == 0 {
+		return "", false
+	}
+
+	var s strings.Builder
+	s.WriteByte('\'')
+	for _, c := range b {
+		if c < 0x20 || c > 0x7E {
+			return "", false
+		}
+		if c == '\'' || c == '\\' {
+			s.WriteByte('\\')
+		}
+		s.WriteByte(c)
+	}
+	s.WriteByte('\'')
+	return s.String(), true
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Integer with fixed bit width that wraps around like C and Go integers.
+type FixedInt struct {
+	// Two's complement bits of the value, bits above Width are always zero.
+	Bits uint64
+	// Bit width from 1 to 64.
+	Width uint8
+	// Is the integer signed.
+	Signed bool
+}
+
+var fixedIntTypes = map[string]FixedInt{
+	"u8":  {Width: 8},
+	"u16": {Width: 16},
+	"u32": {Width: 32},
+	"u64": {Width: 64},
+	"i8":  {Width: 8, Signed: true},
+	"i16": {Width: 16, Signed: true},
+	"i32": {Width: 32, Signed: true},
+	"i64": {Width: 64, Signed: true},
+}
+
+// Create a new fixed-width integer truncating bits to width.
+func NewFixedInt(bits uint64, width uint8, signed bool) FixedInt {
+	i := FixedInt{Width: width, Signed: signed}
+	i.Bits = bits & i.Mask()
+	return i
+}
+
+// Convert any number to the fixed-width integer.
+//
+// The value is truncated to width, floats are truncated towards zero.
+func ToFixedInt(i interface{}, width uint8, signed bool) FixedInt {
+	switch v := i.(type) {
+	case float32, float64, *big.Int:
+		return NewFixedInt(TruncBigInt(ToBigInt(v)), width, signed)
+	}
+	return NewFixedInt(ToNumber[uint64](i), width, signed)
+}
+
+// Find fixed-width integer type by its name (u8, i16 and etc.).
+func FixedIntType(name string) (width uint8, signed bool, found bool) {
+	t, found := fixedIntTypes[name]
+	return t.Width, t.Signed, found
+}
+
+// The mask of the integer width.
+func (i FixedInt) Mask() uint64 {
+	if i.Width >= 64 {
+		return 0xFFFFFFFFFFFFFFFF
+	}
+	return (uint64(1) << i.Width) - 1
+}
+
+// Is the sign bit set and the integer is signed.
+func (i FixedInt) IsNegative() bool {
+	return i.Signed && i.Bits&(uint64(1)<<(i.Width-1)) != 0
+}
+
+// Sign-extended value of the integer.
+func (i FixedInt) Int64() int64 {
+	if i.IsNegative() {
+		return int64(i.Bits | ^i.Mask())
+	}
+	return int64(i.Bits)
+}
+
+// Name of the integer type, like u8 or i32.
+func (i FixedInt) TypeName() string {
+	if i.Signed {
+		return fmt.Sprintf("i%d", i.Width)
+	}
+	return fmt.Sprintf("u%d", i.Width)
+}
+
+// fmt.Stringer interface implementation.
+//
+// Returns the value in the typed literal form, like 255u8 or -1i32.
+func (i FixedInt) String() string {
+	if i.Signed {
+		return strconv.FormatInt(i.Int64(), 10) + i.TypeName()
+	}
+	return strconv.FormatUint(i.Bits, 10) + i.TypeName()
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "fmt"
+
+// Integer value tagged with the name of the user flag set.
+type Flags struct {
+	Bits uint64
+	// Name of the flag set.
+	Set string
+}
+
+// fmt.Stringer interface implementation.
+func (f Flags) String() string {
+	return fmt.Sprintf("0x%X", f.Bits)
+}
@@ -0,0 +1,32 @@
+package utils
+
+type Mode uint
+
+// Calculation modes
+const (
+	// Integer results that don't fit into 64 bits wrap around modulo 2^64
+	// instead of being promoted to arbitrary-precision integers.
+	M_WRAP Mode = 1 << iota
+	// Division of integers produces exact rational numbers.
+	M_RATIONAL
+	// Integer results are also shown as bytes in little-endian and big-endian order.
+	M_BYTES
+	// Integer results with printable bytes are also shown as ASCII characters.
+	M_ASCII
+)
+
+var modes Mode
+
+// Is calculation mode m enabled.
+func ModeEnabled(m Mode) bool {
+	return modes&m != 0
+}
+
+// Enable or disable calculation mode m.
+func SetMode(m Mode, enabled bool) {
+	if enabled {
+		modes |= m
+	} else {
+		modes &^= m
+	}
+}
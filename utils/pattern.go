@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Binary pattern with don't care bits, like 0b1x0x_01xx.
+type Pattern struct {
+	// Mask of the bits that must match.
+	Mask uint64
+	// Values of the bits that must match.
+	Bits uint64
+	// Number of digits in the pattern.
+	Width uint8
+}
+
+// Parse the binary pattern digits, 'x', 'X' and '?' are don't care bits.
+func ParsePattern(s string) (Pattern, error) {
+	var p Pattern
+	for _, c := range s {
+		if c == '_' {
+			continue
+		}
+		if p.Width == 64 {
+			return Pattern{}, fmt.Errorf("the pattern '%s' is wider than 64 bits", s)
+		}
+		p.Mask <<= 1
+		p.Bits <<= 1
+		p.Width++
+		switch c {
+		case '0':
+			p.Mask |= 1
+		case '1':
+			p.Mask |= 1
+			p.Bits |= 1
+		case 'x', 'X', '?':
+		default:
+			return Pattern{}, fmt.Errorf("unexpected digit '%c' in the pattern '%s'", c, s)
+		}
+	}
+	return p, nil
+}
+
+// Mask of the don't care bits.
+func (p Pattern) Wildcards() uint64 {
+	return NewFixedInt(^p.Mask, p.Width, false).Bits
+}
+
+// Does the value v match the pattern, bits above the pattern width must be zero.
+func (p Pattern) Match(v interface{}) bool {
+	x := ToFixedInt(v, 64, false).Bits
+	if f, ok := v.(FixedInt); ok {
+		x = f.Bits
+	}
+	return x&^NewFixedInt(0, p.Width, false).Mask() == 0 && x&p.Mask == p.Bits
+}
+
+// fmt.Stringer interface implementation.
+//
+// Returns the pattern in the literal form, like 0b1x0x01xx.
+func (p Pattern) String() string {
+	var b strings.Builder
+	b.WriteString("0b")
+	for i := int(p.Width) - 1; i >= 0; i-- {
+		switch {
+		case p.Mask>>i&1 == 0:
+			b.WriteByte('x')
+		case p.Bits>>i&1 != 0:
+			b.WriteByte('1')
+		default:
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
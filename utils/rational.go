@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+)
+
+// Convert any number to *big.Rat.
+//
+// Floats are converted exactly, NaN and infinities are converted to zero.
+func ToBigRat(i interface{}) *big.Rat {
+	switch v := i.(type) {
+	case *big.Rat:
+		return new(big.Rat).Set(v)
+	case float32:
+		return ToBigRat(float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return new(big.Rat)
+		}
+		return new(big.Rat).SetFloat64(v)
+	}
+	return new(big.Rat).SetInt(ToBigInt(i))
+}
+
+// Is x a finite number, NaN and infinities can't be converted to *big.Rat.
+func IsFinite(x interface{}) bool {
+	switch v := x.(type) {
+	case float32:
+		return IsFinite(float64(v))
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+	return true
+}
+
+// Convert x to integer if its denominator is 1, otherwise return x itself.
+func NormalizeBigRat(x *big.Rat) interface{} {
+	if x.IsInt() {
+		return NormalizeBigInt(new(big.Int).Set(x.Num()), false)
+	}
+	return x
+}
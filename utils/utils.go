@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math"
+	"math/big"
 	"strings"
 )
 
@@ -13,7 +14,12 @@ const (
 	decLiterals      = "0123456789._"
 	hexLiterals      = "0123456789ABCDEFabcdef_"
 	binLiterals      = "01_"
-	controlLiterals  = "()"
+	patternLiterals  = "01xX?_"
+	octLiterals      = "01234567_"
+	hexFloatLiterals = "0123456789ABCDEFabcdef_.pP"
+	radixLiterals    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz_"
+	suffixLiterals   = "0123456789"
+	controlLiterals  = "()[]"
 	operatorLiterals = ";#?:=-+*/%^!&|~<>,"
 )
 
@@ -35,12 +41,26 @@ const (
 	W_UNIT
 	// Operator.
 	W_OP
-	// Flow control (brackets).
+	// Flow control (brackets and bit slice brackets).
 	W_CTL
 	// Detected function call.
 	W_FUNC
 	// String.
 	W_STR
+	// Number in octal representation.
+	W_NUM_OCT
+	// Number in arbitrary radix representation like 36#ZZ.
+	W_NUM_RADIX
+	// Hexadecimal floating-point number like 0x1.8p3.
+	W_NUM_HEXF
+	// Binary pattern with don't care bits like 0b1x0x.
+	W_NUM_PAT
+	// Byte array like b"DE AD BE EF".
+	W_BYTES
+	// Character literal like 'A'.
+	W_CHAR
+	// Raw string without escape sequences like r"C:\dev".
+	W_RAWSTR
 )
 
 type Word struct {
@@ -67,25 +87,55 @@ func ParsePrompt(str string) []Word {
 
 	wordType := W_NUM_DEC
 	wordDone := false
+	numSuffix := false
+	escaped := false
 
 	wordBegin := -1
 	for i, c := range str {
 		if wordBegin > -1 {
 			switch wordType {
 			case W_UNIT:
+				if c == '"' && str[wordBegin:i] == "b" {
+					// byte array prefix
+					wordType = W_BYTES
+					wordBegin = i + 1
+					continue
+				}
+				if c == '"' && str[wordBegin:i] == "r" {
+					// raw string prefix
+					wordType = W_RAWSTR
+					wordBegin = i + 1
+					continue
+				}
 				if !(strings.Contains(stringLiterals, string(c)) || strings.Contains(decLiterals, string(c))) {
 					wordDone = true
 				}
-			case W_NUM_DEC, W_NUM_HEX, W_NUM_BIN, W_NUM_SCI:
-				if (c == 'x' || c == 'b') && i-wordBegin == 1 && wordType == W_NUM_DEC {
-					if c == 'x' {
+			case W_NUM_DEC, W_NUM_HEX, W_NUM_BIN, W_NUM_SCI, W_NUM_OCT, W_NUM_RADIX, W_NUM_HEXF, W_NUM_PAT:
+				if numSuffix {
+					// integer type suffix like u8 or i32
+					if !strings.Contains(suffixLiterals, string(c)) {
+						wordDone = true
+					}
+				} else if (c == 'u' || c == 'i') && wordType != W_NUM_SCI && wordType != W_NUM_RADIX && wordType != W_NUM_HEXF && wordType != W_NUM_PAT {
+					numSuffix = true
+				} else if (c == 'x' || c == 'b' || c == 'o') && i-wordBegin == 1 && wordType == W_NUM_DEC {
+					switch c {
+					case 'x':
 						wordType = W_NUM_HEX
-					} else {
+					case 'b':
 						wordType = W_NUM_BIN
+					default:
+						wordType = W_NUM_OCT
 					}
 					wordBegin += 2
 				} else if (c == 'e' || c == 'E') && wordType == W_NUM_DEC {
 					wordType = W_NUM_SCI
+				} else if c == '#' && wordType == W_NUM_DEC && strings.Trim(str[wordBegin:i], suffixLiterals) == "" {
+					wordType = W_NUM_RADIX
+				} else if (c == '.' || c == 'p' || c == 'P') && wordType == W_NUM_HEX {
+					wordType = W_NUM_HEXF
+				} else if (c == 'x' || c == 'X' || c == '?') && wordType == W_NUM_BIN {
+					wordType = W_NUM_PAT
 				} else {
 					switch wordType {
 					case W_NUM_SCI:
@@ -104,10 +154,35 @@ func ParsePrompt(str string) []Word {
 						if !strings.Contains(binLiterals, string(c)) {
 							wordDone = true
 						}
+					case W_NUM_OCT:
+						if !strings.Contains(octLiterals, string(c)) {
+							wordDone = true
+						}
+					case W_NUM_PAT:
+						if !strings.Contains(patternLiterals, string(c)) {
+							wordDone = true
+						}
+					case W_NUM_RADIX:
+						if !strings.Contains(radixLiterals, string(c)) {
+							wordDone = true
+						}
+					case W_NUM_HEXF:
+						// exponent sign is allowed only right after p
+						if !strings.Contains(hexFloatLiterals, string(c)) && !((c == '-' || c == '+') && strings.ContainsAny(str[i-1:i], "pP")) {
+							wordDone = true
+						}
 					}
 				}
-			case W_STR:
-				if c == '"' {
+			case W_STR, W_BYTES, W_CHAR, W_RAWSTR:
+				if escaped {
+					escaped = false
+					continue
+				}
+				if c == '\\' && (wordType == W_STR || wordType == W_CHAR) {
+					escaped = true
+					continue
+				}
+				if (c == '"' && wordType != W_CHAR) || (c == '\'' && wordType == W_CHAR) {
 					wordDone = true
 					words = append(words, Word{wordType, str[wordBegin:i]})
 					wordBegin = -1
@@ -130,10 +205,15 @@ func ParsePrompt(str string) []Word {
 		if wordBegin < 0 {
 			wordBegin = i
 			wordDone = false
+			numSuffix = false
+			escaped = false
 
 			if c == '"' {
 				wordType = W_STR
 				wordBegin++
+			} else if c == '\'' {
+				wordType = W_CHAR
+				wordBegin++
 			} else if strings.Contains(stringLiterals, string(c)) {
 				wordType = W_UNIT
 			} else if strings.Contains(decLiterals, string(c)) {
@@ -172,7 +252,9 @@ func ToNumber[T number](i interface{}) T {
 			return T(0)
 		}
 	case string:
-		arr := []byte(v)
+		return ToNumber[T]([]byte(v))
+	case []byte:
+		arr := v
 		var val uint64
 		for i := 0; i < int(math.Min(float64(len(arr)), 8)); i++ {
 			val |= (uint64(arr[i]) << (i * 8))
@@ -188,24 +270,64 @@ func ToNumber[T number](i interface{}) T {
 		return T(v)
 	case uint64:
 		return T(v)
-	case float32:
-		if float64(v)-math.Floor(float64(v)) > 0 {
-			b := make([]byte, 4, 8)
-			b = append(b, ToByteArray(v)...)
-			return FromByteArray[T](b)
+	case *big.Int:
+		var t T
+		if _, isFloat := any(t).(float64); isFloat {
+			f, _ := new(big.Float).SetInt(v).Float64()
+			return T(f)
 		}
-		return T(v)
-	case float64:
-		if v-math.Floor(v) > 0 {
-			b := ToByteArray(v)
-			return FromByteArray[T](b)
+		return T(TruncBigInt(v))
+	case FixedInt:
+		if v.Signed {
+			return T(v.Int64())
 		}
-		return T(v)
+		return T(v.Bits)
+	case Flags:
+		return T(v.Bits)
+	case *big.Rat:
+		var t T
+		if _, isFloat := any(t).(float64); isFloat {
+			f, _ := v.Float64()
+			return T(f)
+		}
+		return T(TruncBigInt(ToBigInt(v)))
+	case complex128:
+		return ToNumber[T](real(v))
+	case float32:
+		return floatToNumber[T](float64(v))
+	case float64:
+		return floatToNumber[T](v)
 	}
 
 	return T(0)
 }
 
+// Convert float to number T truncating it towards zero.
+//
+// Negative floats are converted to unsigned numbers in two's complement,
+// out of range values are saturated and NaN is converted to zero.
+func floatToNumber[T number](v float64) T {
+	var t T
+	if _, isFloat := any(t).(float64); isFloat {
+		return T(v)
+	}
+	switch {
+	case math.IsNaN(v):
+		return T(0)
+	case v < 0:
+		i := int64(math.MinInt64)
+		if v > math.MinInt64 {
+			i = int64(v)
+		}
+		return T(i)
+	}
+	u := uint64(math.MaxUint64)
+	if v < math.MaxUint64 {
+		u = uint64(v)
+	}
+	return T(u)
+}
+
 // Convert any variable to bool.
 //
 // In most cases it returns true if i > 0.
@@ -215,6 +337,8 @@ func ToBool(i interface{}) bool {
 		return v
 	case string:
 		return len(v) > 0
+	case []byte:
+		return len(v) > 0
 	case byte:
 		return v > 0
 	case int:
@@ -227,6 +351,16 @@ func ToBool(i interface{}) bool {
 		return v > 0
 	case float64:
 		return v > 0
+	case *big.Int:
+		return v.Sign() > 0
+	case FixedInt:
+		return v.Int64() > 0 || !v.Signed && v.Bits > 0
+	case Flags:
+		return v.Bits > 0
+	case *big.Rat:
+		return v.Sign() > 0
+	case complex128:
+		return v != 0
 	}
 
 	return false
@@ -234,15 +368,25 @@ func ToBool(i interface{}) bool {
 
 // Helper function that converts byte slice to type T.
 func FromByteArray[T any](b []byte) (s T) {
-	buf := bytes.NewReader(b)
-	binary.Read(buf, binary.LittleEndian, &s)
-	return
+	return FromByteArrayOrder[T](b, binary.LittleEndian)
 }
 
 // Helper function that converts type T to byte slice.
 func ToByteArray[T any](s T) (b []byte) {
+	return ToByteArrayOrder(s, binary.LittleEndian)
+}
+
+// Helper function that converts byte slice with the given byte order to type T.
+func FromByteArrayOrder[T any](b []byte, order binary.ByteOrder) (s T) {
+	buf := bytes.NewReader(b)
+	binary.Read(buf, order, &s)
+	return
+}
+
+// Helper function that converts type T to byte slice with the given byte order.
+func ToByteArrayOrder[T any](s T, order binary.ByteOrder) (b []byte) {
 	buf := new(bytes.Buffer)
-	binary.Write(buf, binary.LittleEndian, s)
+	binary.Write(buf, order, s)
 	b = buf.Bytes()
 	return
 }